@@ -4,6 +4,11 @@ go 1.22.0
 
 toolchain go1.22.2
 
-require github.com/coinbase/x402/go v0.0.0
+require (
+	github.com/coinbase/x402/go v0.0.0
+	golang.org/x/crypto v0.31.0
+)
+
+require golang.org/x/sys v0.28.0 // indirect
 
 replace github.com/coinbase/x402/go => ../x402_repo/go
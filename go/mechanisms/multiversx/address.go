@@ -0,0 +1,103 @@
+package multiversx
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	// DefaultHRP is the human readable part of mainnet, devnet and testnet addresses
+	DefaultHRP = "erd"
+
+	// PubKeyLength is the length of an account public key
+	PubKeyLength = 32
+
+	// smartContractPrefixLength zero bytes open every smart contract address ("erd1qqqqqqqq...")
+	smartContractPrefixLength = 8
+)
+
+// Address is a MultiversX account address: a 32-byte public key plus the HRP used to display it
+type Address struct {
+	pubKey []byte
+	hrp    string
+}
+
+// AddressFromBech32 parses an address such as "erd1..." (or another HRP for custom chains)
+func AddressFromBech32(bech string) (Address, error) {
+	hrp, pubKey, err := DecodeBech32(bech)
+	if err != nil {
+		return Address{}, fmt.Errorf("invalid bech32 address %q: %v", bech, err)
+	}
+	return AddressFromPubKey(pubKey, hrp)
+}
+
+// AddressFromHex parses a hex encoded public key. An empty hrp defaults to DefaultHRP.
+func AddressFromHex(pubKeyHex string, hrp string) (Address, error) {
+	pubKey, err := hex.DecodeString(pubKeyHex)
+	if err != nil {
+		return Address{}, fmt.Errorf("invalid hex address %q", pubKeyHex)
+	}
+	return AddressFromPubKey(pubKey, hrp)
+}
+
+// AddressFromPubKey wraps a 32-byte public key. An empty hrp defaults to DefaultHRP.
+func AddressFromPubKey(pubKey []byte, hrp string) (Address, error) {
+	if len(pubKey) != PubKeyLength {
+		return Address{}, fmt.Errorf("invalid public key length: %d", len(pubKey))
+	}
+	if hrp == "" {
+		hrp = DefaultHRP
+	}
+	return Address{
+		pubKey: append([]byte(nil), pubKey...),
+		hrp:    hrp,
+	}, nil
+}
+
+// Bech32 returns the bech32 representation ("erd1...")
+func (a Address) Bech32() string {
+	if a.IsEmpty() {
+		return ""
+	}
+	// Length and HRP were validated on construction
+	bech, _ := EncodeBech32(a.hrp, a.pubKey)
+	return bech
+}
+
+// Hex returns the hex encoded public key, as used in transaction data arguments
+func (a Address) Hex() string {
+	return hex.EncodeToString(a.pubKey)
+}
+
+// PubKey returns a copy of the public key
+func (a Address) PubKey() []byte {
+	return append([]byte(nil), a.pubKey...)
+}
+
+// HRP returns the human readable part used by Bech32
+func (a Address) HRP() string {
+	return a.hrp
+}
+
+// IsEmpty reports whether the address is the zero value
+func (a Address) IsEmpty() bool {
+	return len(a.pubKey) == 0
+}
+
+// IsSmartContract reports whether the address belongs to a smart contract
+func (a Address) IsSmartContract() bool {
+	if len(a.pubKey) != PubKeyLength {
+		return false
+	}
+	return bytes.Equal(a.pubKey[:smartContractPrefixLength], make([]byte, smartContractPrefixLength))
+}
+
+// Equal compares the public keys; the HRP is display only
+func (a Address) Equal(other Address) bool {
+	return bytes.Equal(a.pubKey, other.pubKey)
+}
+
+func (a Address) String() string {
+	return a.Bech32()
+}
@@ -0,0 +1,108 @@
+package multiversx
+
+import (
+	"strings"
+	"testing"
+)
+
+// esdtSystemSC is the ESDT system smart contract address
+const esdtSystemSC = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqllls0lczs7"
+
+func TestEncodeBech32_RoundTrip(t *testing.T) {
+	for _, addr := range []string{aliceAddress, bobAddress, carolAddress, esdtSystemSC} {
+		hrp, data, err := DecodeBech32(addr)
+		if err != nil {
+			t.Fatalf("decode %s: %v", addr, err)
+		}
+		encoded, err := EncodeBech32(hrp, data)
+		if err != nil {
+			t.Fatalf("encode %s: %v", addr, err)
+		}
+		if encoded != addr {
+			t.Errorf("round trip mismatch: expected %s, got %s", addr, encoded)
+		}
+	}
+
+	if _, err := EncodeBech32("ERD", make([]byte, 32)); err == nil {
+		t.Error("Expected error for uppercase hrp")
+	}
+}
+
+func TestDecodeBech32_Case(t *testing.T) {
+	// All uppercase is valid BIP-173
+	if _, _, err := DecodeBech32(strings.ToUpper(bobAddress)); err != nil {
+		t.Errorf("Expected uppercase address to decode, got %v", err)
+	}
+
+	// Mixed case is not
+	mixed := "erd1SPyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx"
+	if _, _, err := DecodeBech32(mixed); err == nil {
+		t.Error("Expected mixed case address to be rejected")
+	}
+	if IsValidAddress(mixed) {
+		t.Error("IsValidAddress should reject mixed case")
+	}
+}
+
+func TestAddress_Conversions(t *testing.T) {
+	fromBech, err := AddressFromBech32(bobAddress)
+	if err != nil {
+		t.Fatalf("AddressFromBech32: %v", err)
+	}
+	if fromBech.Hex() != bobPubKeyHex {
+		t.Errorf("Expected hex %s, got %s", bobPubKeyHex, fromBech.Hex())
+	}
+
+	fromHex, err := AddressFromHex(bobPubKeyHex, "")
+	if err != nil {
+		t.Fatalf("AddressFromHex: %v", err)
+	}
+	if fromHex.Bech32() != bobAddress {
+		t.Errorf("Expected %s, got %s", bobAddress, fromHex.Bech32())
+	}
+	if !fromHex.Equal(fromBech) {
+		t.Error("Expected addresses to be equal")
+	}
+
+	upper, err := AddressFromBech32(strings.ToUpper(bobAddress))
+	if err != nil || upper.Bech32() != bobAddress {
+		t.Errorf("Expected uppercase input to normalize to %s, got %s (%v)", bobAddress, upper.Bech32(), err)
+	}
+
+	// Custom chains use their own HRP
+	custom, err := AddressFromPubKey(fromBech.PubKey(), "test")
+	if err != nil {
+		t.Fatalf("AddressFromPubKey: %v", err)
+	}
+	if !strings.HasPrefix(custom.Bech32(), "test1") {
+		t.Errorf("Expected test1 prefix, got %s", custom.Bech32())
+	}
+	if IsValidAddress(custom.Bech32()) {
+		t.Error("IsValidAddress should reject a foreign HRP")
+	}
+	if !IsValidAddressWithHRP(custom.Bech32(), "test") {
+		t.Error("IsValidAddressWithHRP should accept the custom HRP")
+	}
+
+	if _, err := AddressFromHex("abcd", ""); err == nil {
+		t.Error("Expected error for short public key")
+	}
+	if _, err := AddressFromHex("zz", ""); err == nil {
+		t.Error("Expected error for invalid hex")
+	}
+}
+
+func TestAddress_IsSmartContract(t *testing.T) {
+	sc, err := AddressFromBech32(esdtSystemSC)
+	if err != nil {
+		t.Fatalf("AddressFromBech32: %v", err)
+	}
+	if !sc.IsSmartContract() {
+		t.Error("Expected system SC to be a smart contract")
+	}
+
+	bob, _ := AddressFromBech32(bobAddress)
+	if bob.IsSmartContract() {
+		t.Error("Expected user account not to be a smart contract")
+	}
+}
@@ -45,16 +45,10 @@ func DecodeBech32(bech string) (string, []byte, error) {
 		return "", nil, fmt.Errorf("invalid bech32 string length")
 	}
 
-	// Force lowercase for processing (BIP-173 requires checking for mixed case,
-	// but here we just decode. Validator should fail mixed case if strict,
-	// but we'll accept lenient-cased inputs by lowering.)
-	// Note: Standard requires "Is Mixed Case?" check.
-	// For simplicity, we lower everything.
+	// BIP-173: "Decoders MUST NOT accept strings with mixed upper and lower case letters."
+	// All-uppercase strings are valid and decoded as lowercase.
 	if strings.ToLower(bech) != bech && strings.ToUpper(bech) != bech {
-		// Mixed case is invalid according to spec, but we can be lenient or strict.
-		// Standard: "Decoders MUST NOT accept strings with mixed upper and lower case letters."
-		// So we SHOULD return error if mixed.
-		// However, for MultiversX robustness, we will just ToLower it to verify checksum.
+		return "", nil, fmt.Errorf("invalid bech32 string: mixed case")
 	}
 	bechLower := strings.ToLower(bech)
 
@@ -91,6 +85,55 @@ func DecodeBech32(bech string) (string, []byte, error) {
 	return hrp, decoded, nil
 }
 
+// EncodeBech32 encodes data (e.g. a 32-byte public key) as a bech32 string with the given HRP
+func EncodeBech32(hrp string, data []byte) (string, error) {
+	if hrp == "" || strings.ToLower(hrp) != hrp {
+		return "", fmt.Errorf("invalid hrp: %q", hrp)
+	}
+
+	ints := make([]int, len(data))
+	for i, b := range data {
+		ints[i] = int(b)
+	}
+
+	// Convert 8-bit to 5-bit
+	converted, err := convertBits(ints, 8, 5, true)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert bits: %v", err)
+	}
+
+	values := make([]int, len(converted))
+	for i, b := range converted {
+		values[i] = int(b)
+	}
+	checksum := bech32CreateChecksum(hrp, values)
+
+	var sb strings.Builder
+	sb.Grow(len(hrp) + 1 + len(values) + len(checksum))
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range append(values, checksum...) {
+		sb.WriteByte(charset[v])
+	}
+
+	if sb.Len() > 90 {
+		return "", fmt.Errorf("invalid bech32 string length")
+	}
+	return sb.String(), nil
+}
+
+func bech32CreateChecksum(hrp string, data []int) []int {
+	values := append(bech32HrpExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	polymod := bech32Polymod(values) ^ 1
+
+	checksum := make([]int, 6)
+	for i := 0; i < 6; i++ {
+		checksum[i] = (polymod >> uint(5*(5-i))) & 31
+	}
+	return checksum
+}
+
 func convertBits(data []int, fromBits int, toBits int, pad bool) ([]byte, error) {
 	acc := 0
 	bits := 0
@@ -0,0 +1,212 @@
+package multiversx
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coinbase/x402/go/types"
+)
+
+// Requirements Extra keys binding a payment to a resource and a validity window
+const (
+	// ExtraKeyResourceID binds a payment to one invoice/resource
+	ExtraKeyResourceID = "resourceId"
+	// ExtraKeyIssuedAt is when the server issued the requirements (unix seconds, informational)
+	ExtraKeyIssuedAt = "issuedAt"
+	// ExtraKeyValidUntil is the last second (unix) the payment may be verified or settled
+	ExtraKeyValidUntil = "validUntil"
+)
+
+// maxResourceIDLength keeps the data field (and its gas) small
+const maxResourceIDLength = 128
+
+// PaymentBinding is what the client writes after the transfer so the signed transaction
+// pays for one resource only, and only until ValidUntil:
+//
+//	EGLD: data = <resourceId>[@<validUntilHex>]
+//	ESDT: <transfer>@<resourceIdHex>[@<validUntilHex>]
+type PaymentBinding struct {
+	ResourceID string
+	ValidUntil uint64 // unix seconds, 0 when the payment does not expire
+}
+
+// IsEmpty reports whether the requirements bind nothing
+func (b PaymentBinding) IsEmpty() bool {
+	return b.ResourceID == ""
+}
+
+// Expired reports whether the validity window closed before now
+func (b PaymentBinding) Expired(now time.Time) bool {
+	return b.ValidUntil > 0 && now.Unix() > int64(b.ValidUntil)
+}
+
+// EGLDData returns the data field of a bound EGLD transfer
+func (b PaymentBinding) EGLDData() string {
+	if b.ValidUntil == 0 {
+		return b.ResourceID
+	}
+	return b.ResourceID + "@" + encodeUint(b.ValidUntil)
+}
+
+// Matches reports whether tx carries exactly this binding after its transfer
+func (b PaymentBinding) Matches(tx Transaction) bool {
+	if !IsESDTTransferData(tx.Data) {
+		return tx.Data == b.EGLDData()
+	}
+	transfer, err := DecodeTransferData(tx.Data, "")
+	if err != nil || transfer.CallFunction != b.ResourceID {
+		return false
+	}
+	expected := b.callArgs()
+	if len(transfer.CallArgs) != len(expected) {
+		return false
+	}
+	for i := range expected {
+		if !bytes.Equal(transfer.CallArgs[i], expected[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// callArgs returns the arguments following the resourceId after an ESDT transfer
+func (b PaymentBinding) callArgs() [][]byte {
+	if b.ValidUntil == 0 {
+		return nil
+	}
+	arg, _ := hex.DecodeString(encodeUint(b.ValidUntil))
+	return [][]byte{arg}
+}
+
+// ValidateResourceID accepts 1 to 128 characters out of letters, digits and "._:-", so the
+// ID can never be read as a built-in function call or an argument separator in the data field
+func ValidateResourceID(id string) error {
+	if id == "" || len(id) > maxResourceIDLength {
+		return fmt.Errorf("resourceId must have 1 to %d characters", maxResourceIDLength)
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '_' || c == ':' || c == '-') {
+			return fmt.Errorf("invalid character %q in resourceId", c)
+		}
+	}
+	return nil
+}
+
+// NewResourceID returns an unguessable resource ID (128 random bits, hex)
+func NewResourceID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate resourceId: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// RequiredBinding reads the resourceId and validUntil of the requirements.
+// An expiry needs a resourceId to be written after.
+func RequiredBinding(requirements types.PaymentRequirements) (PaymentBinding, error) {
+	var b PaymentBinding
+	if raw, ok := requirements.Extra[ExtraKeyResourceID]; ok && raw != nil {
+		id, ok := raw.(string)
+		if !ok {
+			return b, fmt.Errorf("resourceId must be a string")
+		}
+		if id != "" {
+			if err := ValidateResourceID(id); err != nil {
+				return b, err
+			}
+			b.ResourceID = id
+		}
+	}
+
+	if raw, ok := requirements.Extra[ExtraKeyValidUntil]; ok && raw != nil {
+		validUntil, err := extraUint64(raw)
+		if err != nil || validUntil == 0 {
+			return b, fmt.Errorf("invalid validUntil %v", raw)
+		}
+		if b.ResourceID == "" {
+			return b, fmt.Errorf("validUntil requires a resourceId")
+		}
+		b.ValidUntil = validUntil
+	}
+	return b, nil
+}
+
+// TransactionBinding reads the binding written in the data field by NewPaymentTransaction
+func TransactionBinding(tx Transaction) (PaymentBinding, error) {
+	var b PaymentBinding
+	if !IsESDTTransferData(tx.Data) {
+		if tx.Data == "" {
+			return b, nil
+		}
+		parts := strings.Split(tx.Data, "@")
+		if len(parts) > 2 {
+			return b, fmt.Errorf("unexpected arguments after resourceId %q", parts[0])
+		}
+		b.ResourceID = parts[0]
+		if len(parts) == 2 {
+			if err := checkHexArg(parts[1]); err != nil {
+				return b, fmt.Errorf("invalid validUntil: %v", err)
+			}
+			validUntil, err := decodeUint(parts[1])
+			if err != nil {
+				return b, fmt.Errorf("invalid validUntil: %v", err)
+			}
+			b.ValidUntil = validUntil
+		}
+		return b, nil
+	}
+
+	transfer, err := DecodeTransferData(tx.Data, "")
+	if err != nil {
+		return b, err
+	}
+	b.ResourceID = transfer.CallFunction
+	switch len(transfer.CallArgs) {
+	case 0:
+	case 1:
+		validUntil, err := decodeUint(hex.EncodeToString(transfer.CallArgs[0]))
+		if err != nil {
+			return b, fmt.Errorf("invalid validUntil: %v", err)
+		}
+		b.ValidUntil = validUntil
+	default:
+		return b, fmt.Errorf("unexpected arguments after resourceId %q", b.ResourceID)
+	}
+	return b, nil
+}
+
+// extraUint64 reads a non-negative integer from Extra: a JSON number (float64 once decoded),
+// a Go integer, or a decimal string
+func extraUint64(v interface{}) (uint64, error) {
+	switch n := v.(type) {
+	case float64:
+		if n < 0 || n != math.Trunc(n) || n > math.MaxInt64 {
+			return 0, fmt.Errorf("not a non-negative integer: %v", n)
+		}
+		return uint64(n), nil
+	case int:
+		if n < 0 {
+			return 0, fmt.Errorf("negative value %d", n)
+		}
+		return uint64(n), nil
+	case int64:
+		if n < 0 {
+			return 0, fmt.Errorf("negative value %d", n)
+		}
+		return uint64(n), nil
+	case uint64:
+		return n, nil
+	case json.Number:
+		return strconv.ParseUint(n.String(), 10, 64)
+	case string:
+		return strconv.ParseUint(n, 10, 64)
+	}
+	return 0, fmt.Errorf("unsupported type %T", v)
+}
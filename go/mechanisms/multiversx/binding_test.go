@@ -0,0 +1,98 @@
+package multiversx
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/coinbase/x402/go/types"
+)
+
+func TestPaymentBinding_RoundTrip(t *testing.T) {
+	bob, _ := AddressFromBech32(bobAddress)
+	bindings := []PaymentBinding{
+		{ResourceID: "inv_123"},
+		{ResourceID: "inv_123", ValidUntil: 1700000000},
+	}
+
+	for _, b := range bindings {
+		esdtData, err := TransferData{
+			Function:     FunctionMultiESDTNFTTransfer,
+			Receiver:     bob,
+			Transfers:    []TokenTransfer{{Identifier: "USDC-c76f1f", Amount: big.NewInt(100)}},
+			CallFunction: b.ResourceID,
+			CallArgs:     b.callArgs(),
+		}.Encode()
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+
+		for _, tx := range []Transaction{{Data: b.EGLDData()}, {Data: esdtData}} {
+			if !b.Matches(tx) {
+				t.Errorf("%+v does not match its own data %q", b, tx.Data)
+			}
+			got, err := TransactionBinding(tx)
+			if err != nil || got != b {
+				t.Errorf("TransactionBinding(%q) = %+v (%v), expected %+v", tx.Data, got, err, b)
+			}
+		}
+	}
+
+	// Another expiry, or none, is another binding
+	signed := PaymentBinding{ResourceID: "inv_123", ValidUntil: 1700000000}
+	for _, data := range []string{"inv_123", "inv_123@6553f101", "inv_123@6553f100@01", "inv_124@6553f100"} {
+		if signed.Matches(Transaction{Data: data}) {
+			t.Errorf("%+v must not match %q", signed, data)
+		}
+	}
+	if signed.EGLDData() != "inv_123@6553f100" {
+		t.Errorf("Unexpected EGLD data %q", signed.EGLDData())
+	}
+}
+
+func TestPaymentBinding_Expired(t *testing.T) {
+	b := PaymentBinding{ResourceID: "inv_123", ValidUntil: 1700000000}
+	if b.Expired(time.Unix(1700000000, 0)) {
+		t.Error("validUntil is inclusive")
+	}
+	if !b.Expired(time.Unix(1700000001, 0)) {
+		t.Error("Expected the binding to expire after validUntil")
+	}
+	if (PaymentBinding{ResourceID: "inv_123"}).Expired(time.Now()) {
+		t.Error("No validUntil never expires")
+	}
+}
+
+func TestRequiredBinding(t *testing.T) {
+	// Extra as received by the client: numbers decoded as float64
+	var extra map[string]interface{}
+	json.Unmarshal([]byte(`{"resourceId":"inv_123","issuedAt":1699999940,"validUntil":1700000000}`), &extra)
+	b, err := RequiredBinding(types.PaymentRequirements{Extra: extra})
+	if err != nil || b != (PaymentBinding{ResourceID: "inv_123", ValidUntil: 1700000000}) {
+		t.Errorf("Unexpected binding %+v (%v)", b, err)
+	}
+
+	// As stamped by a Go server
+	b, err = RequiredBinding(types.PaymentRequirements{Extra: map[string]interface{}{
+		ExtraKeyResourceID: "inv_123",
+		ExtraKeyValidUntil: int64(1700000000),
+	}})
+	if err != nil || b.ValidUntil != 1700000000 {
+		t.Errorf("Unexpected binding %+v (%v)", b, err)
+	}
+
+	invalid := []map[string]interface{}{
+		{ExtraKeyValidUntil: 1700000000},                                // expiry without resourceId
+		{ExtraKeyResourceID: "inv_123", ExtraKeyValidUntil: -5},         // negative
+		{ExtraKeyResourceID: "inv_123", ExtraKeyValidUntil: 1.5},        // fractional
+		{ExtraKeyResourceID: "inv_123", ExtraKeyValidUntil: "tomorrow"}, // not a number
+		{ExtraKeyResourceID: "inv 123"},                                 // unsafe resourceId
+		{ExtraKeyResourceID: 123},                                       // not a string
+	}
+	for _, e := range invalid {
+		if _, err := RequiredBinding(types.PaymentRequirements{Extra: e}); err == nil {
+			t.Errorf("Expected an error for %v", e)
+		}
+	}
+}
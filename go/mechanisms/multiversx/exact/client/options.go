@@ -0,0 +1,40 @@
+package client
+
+import (
+	"x402-integration/mechanisms/multiversx"
+)
+
+// Option configures the client scheme
+type Option func(*ExactMultiversXScheme)
+
+// WithNetworkProvider sets the source of nonces and network parameters.
+// Without it the public gateway of the payment's chain (1, D or T) is used.
+func WithNetworkProvider(provider multiversx.NetworkProvider) Option {
+	return func(s *ExactMultiversXScheme) {
+		s.provider = provider
+	}
+}
+
+// WithNonceManager shares a nonce manager, e.g. between several schemes paying from the same wallet.
+// By default each scheme keeps one manager per chain on top of its network provider.
+func WithNonceManager(manager *multiversx.NonceManager) Option {
+	return func(s *ExactMultiversXScheme) {
+		s.nonces = manager
+	}
+}
+
+// WithTransactionCostRefinement asks the network provider for the exact gas cost
+// (POST /transaction/cost) instead of relying on the local gas schedule alone
+func WithTransactionCostRefinement(enabled bool) Option {
+	return func(s *ExactMultiversXScheme) {
+		s.refineGas = enabled
+	}
+}
+
+// WithTransferFormat selects the token payment layout: MultiESDTNFTTransfer self-transfer (default)
+// or a direct ESDTTransfer to PayTo
+func WithTransferFormat(format multiversx.TransferFormat) Option {
+	return func(s *ExactMultiversXScheme) {
+		s.transferFormat = format
+	}
+}
@@ -5,23 +5,37 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"math/big"
-	"strings"
+	"sync"
 
 	"x402-integration/mechanisms/multiversx"
 
+	x402 "github.com/coinbase/x402/go"
 	"github.com/coinbase/x402/go/types"
 )
 
 // ExactMultiversXScheme implements SchemeNetworkClient
 type ExactMultiversXScheme struct {
-	signer multiversx.ClientMultiversXSigner
+	signer         multiversx.ClientMultiversXSigner
+	provider       multiversx.NetworkProvider
+	nonces         *multiversx.NonceManager
+	refineGas      bool
+	transferFormat multiversx.TransferFormat
+
+	mu       sync.Mutex
+	gateways map[string]multiversx.NetworkProvider // default providers per chain ID
+	managers map[string]*multiversx.NonceManager   // nonce managers per chain ID
 }
 
-func NewExactMultiversXScheme(signer multiversx.ClientMultiversXSigner) *ExactMultiversXScheme {
-	return &ExactMultiversXScheme{
-		signer: signer,
+func NewExactMultiversXScheme(signer multiversx.ClientMultiversXSigner, opts ...Option) *ExactMultiversXScheme {
+	s := &ExactMultiversXScheme{
+		signer:   signer,
+		gateways: make(map[string]multiversx.NetworkProvider),
+		managers: make(map[string]*multiversx.NonceManager),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *ExactMultiversXScheme) Scheme() string {
@@ -33,132 +47,70 @@ func (s *ExactMultiversXScheme) GetSigners(ctx context.Context) ([]string, error
 }
 
 func (s *ExactMultiversXScheme) CreatePaymentPayload(ctx context.Context, requirements types.PaymentRequirements) (types.PaymentPayload, error) {
-	// 1. Validate inputs
-	if requirements.PayTo == "" {
-		return types.PaymentPayload{}, fmt.Errorf("PayTo is required")
+	// 1. Build the transfer (EGLD, or a token transfer in the chosen format; Relayed V3 if a relayer is advertised)
+	sender := s.signer.Address()
+	tx, err := multiversx.NewPaymentTransaction(requirements, sender, s.transferFormat)
+	if err != nil {
+		return types.PaymentPayload{}, err
 	}
 
-	// 2. Prepare Transaction Data
-	// TODO: Fetch Nonce/Gas from network or allow RPC injection
-	gasLimit := uint64(50000)
-	gasPrice := uint64(1000000000)
+	// 2. Gas from the network schedule (data length, built-in transfer cost, relayer overhead)
+	provider, err := s.networkProvider(tx.ChainID)
+	if err != nil {
+		return types.PaymentPayload{}, err
+	}
+	tx.GasLimit, tx.GasPrice, err = multiversx.NewGasEstimator(provider, s.refineGas).Estimate(ctx, tx)
+	if err != nil {
+		return types.PaymentPayload{}, fmt.Errorf("failed to estimate gas: %v", err)
+	}
 
-	version := uint32(1)
-	chainID := "D" // Default Devnet
-	if requirements.Network != "" {
-		// Clean handling of ChainID from Network string
-		parts := strings.Split(string(requirements.Network), ":")
-		if len(parts) > 1 {
-			chainID = parts[1]
-		}
+	// 3. Reserve a nonce; it goes back to the pool if the payload is never produced
+	nonces, err := s.nonceManager(tx.ChainID)
+	if err != nil {
+		return types.PaymentPayload{}, err
+	}
+	nonce, err := nonces.Reserve(ctx, sender)
+	if err != nil {
+		return types.PaymentPayload{}, err
 	}
+	tx.Nonce = nonce
+	payload, err := s.signPayload(ctx, tx)
+	if err != nil {
+		nonces.Release(sender, nonce)
+		return types.PaymentPayload{}, err
+	}
+	return payload, nil
+}
 
-	sender := s.signer.Address()
-	receiver := requirements.PayTo
-	value := requirements.Amount
-
-	// ESDT Logic
-	dataString := ""
-	asset := requirements.Asset
-
-	if asset != "" && asset != "EGLD" {
-		// ESDT Transfer (MultiESDTNFTTransfer)
-		// Receiver becomes Sender (Self Transfer)
-		receiver = sender
-		value = "0"
-		gasLimit = 60000000 // Higher gas for ESDT
-
-		// Encode Data: MultiESDTNFTTransfer@<DestHex>@01@<TokenHex>@00@<AmountHex>
-		var destHex string
-		if strings.HasPrefix(requirements.PayTo, "erd1") {
-			_, decodedBytes, err := multiversx.DecodeBech32(requirements.PayTo)
-			if err == nil {
-				destHex = hex.EncodeToString(decodedBytes)
-			} else {
-				destHex = hex.EncodeToString([]byte(requirements.PayTo))
-			}
-		} else {
-			destHex = hex.EncodeToString([]byte(requirements.PayTo))
-		}
-
-		tokenHex := hex.EncodeToString([]byte(requirements.Asset))
-
-		amtBig, _ := new(big.Int).SetString(requirements.Amount, 10)
-		if amtBig == nil {
-			return types.PaymentPayload{}, fmt.Errorf("invalid amount")
-		}
-		amtHex := amtBig.Text(16)
-		if len(amtHex)%2 != 0 {
-			amtHex = "0" + amtHex
-		}
-
-		// Extract ResourceID from Extra if present
-		var resourceIdHex string
-		if rid, ok := requirements.Extra["resourceId"].(string); ok && rid != "" {
-			resourceIdHex = hex.EncodeToString([]byte(rid))
-		}
-
-		if resourceIdHex != "" {
-			dataString = fmt.Sprintf("MultiESDTNFTTransfer@%s@01@%s@00@%s@%s", destHex, tokenHex, amtHex, resourceIdHex)
-		} else {
-			dataString = fmt.Sprintf("MultiESDTNFTTransfer@%s@01@%s@00@%s", destHex, tokenHex, amtHex)
-		}
-
-	} else {
-		// EGLD
-		dataString = ""
-	}
-
-	// 3. Construct Payload Object
-	txData := struct {
-		Nonce    uint64 `json:"nonce"`
-		Value    string `json:"value"`
-		Receiver string `json:"receiver"`
-		Sender   string `json:"sender"`
-		GasPrice uint64 `json:"gasPrice"`
-		GasLimit uint64 `json:"gasLimit"`
-		Data     string `json:"data,omitempty"`
-		ChainID  string `json:"chainID"`
-		Version  uint32 `json:"version"`
-	}{
-		Nonce:    15, // TODO: Fetch nonce
-		Value:    value,
-		Receiver: receiver,
-		Sender:   sender,
-		GasPrice: gasPrice,
-		GasLimit: gasLimit,
-		Data:     dataString,
-		ChainID:  chainID,
-		Version:  version,
-	}
-
-	// 4. Serialize for Signing (Canonical JSON)
-	txBytes, err := json.Marshal(txData)
+// signPayload signs the transaction as sender and wraps it into an x402 payload
+func (s *ExactMultiversXScheme) signPayload(ctx context.Context, tx multiversx.Transaction) (types.PaymentPayload, error) {
+	// 1. Construct Payload Object
+	relayedPayload := multiversx.ExactRelayedPayload{
+		Scheme: multiversx.SchemeExact,
+		Data:   tx,
+	}
+
+	// 2. Serialize for Signing (Canonical JSON, matching the node)
+	txBytes, err := relayedPayload.Data.BytesForSigning()
 	if err != nil {
 		return types.PaymentPayload{}, err
 	}
 
-	// 5. Sign
+	// 3. Sign
 	sigBytes, err := s.signer.Sign(ctx, txBytes)
 	if err != nil {
 		return types.PaymentPayload{}, err
 	}
+	relayedPayload.Data.Signature = hex.EncodeToString(sigBytes)
 
-	// 6. Build Final Payload Map directly
-	finalMap := map[string]interface{}{
-		"scheme": multiversx.SchemeExact,
-		"data": map[string]interface{}{
-			"nonce":     txData.Nonce,
-			"value":     txData.Value,
-			"receiver":  txData.Receiver,
-			"sender":    txData.Sender,
-			"gasPrice":  txData.GasPrice,
-			"gasLimit":  txData.GasLimit,
-			"data":      txData.Data,
-			"chainID":   txData.ChainID,
-			"version":   txData.Version,
-			"signature": hex.EncodeToString(sigBytes),
-		},
+	// 4. Build Final Payload Map
+	payloadBytes, err := json.Marshal(relayedPayload)
+	if err != nil {
+		return types.PaymentPayload{}, err
+	}
+	var finalMap map[string]interface{}
+	if err := json.Unmarshal(payloadBytes, &finalMap); err != nil {
+		return types.PaymentPayload{}, err
 	}
 
 	return types.PaymentPayload{
@@ -166,3 +118,97 @@ func (s *ExactMultiversXScheme) CreatePaymentPayload(ctx context.Context, requir
 		Payload:     finalMap,
 	}, nil
 }
+
+// AbandonPayment returns the nonce of a payload that will never be settled,
+// so the next payment reuses it instead of leaving a gap.
+func (s *ExactMultiversXScheme) AbandonPayment(payload types.PaymentPayload) error {
+	tx, err := decodeTransaction(payload)
+	if err != nil {
+		return err
+	}
+	nonces, err := s.nonceManager(tx.ChainID)
+	if err != nil {
+		return err
+	}
+	nonces.Release(tx.Sender, tx.Nonce)
+	return nil
+}
+
+// HandleSettleResponse updates the local nonce state from the facilitator's answer:
+// nonce errors trigger a resync, and failures that never reached the chain free the nonce.
+func (s *ExactMultiversXScheme) HandleSettleResponse(ctx context.Context, payload types.PaymentPayload, resp *x402.SettleResponse) error {
+	if resp == nil || resp.Success {
+		return nil
+	}
+	tx, err := decodeTransaction(payload)
+	if err != nil {
+		return err
+	}
+	nonces, err := s.nonceManager(tx.ChainID)
+	if err != nil {
+		return err
+	}
+
+	if resynced, err := nonces.HandleError(ctx, tx.Sender, resp.ErrorReason); resynced {
+		return err
+	}
+	if !multiversx.IsBroadcastReason(resp.ErrorReason) {
+		nonces.Release(tx.Sender, tx.Nonce)
+	}
+	return nil
+}
+
+func decodeTransaction(payload types.PaymentPayload) (multiversx.Transaction, error) {
+	var rp multiversx.ExactRelayedPayload
+	payloadBytes, err := json.Marshal(payload.Payload)
+	if err != nil {
+		return rp.Data, err
+	}
+	if err := json.Unmarshal(payloadBytes, &rp); err != nil {
+		return rp.Data, fmt.Errorf("invalid payload format: %v", err)
+	}
+	return rp.Data, nil
+}
+
+// nonceManager returns the configured manager, or one per chain on top of its network provider
+func (s *ExactMultiversXScheme) nonceManager(chainID string) (*multiversx.NonceManager, error) {
+	if s.nonces != nil {
+		return s.nonces, nil
+	}
+
+	provider, err := s.networkProvider(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.managers[chainID]
+	if !ok {
+		m = multiversx.NewNonceManager(provider)
+		s.managers[chainID] = m
+	}
+	return m, nil
+}
+
+// networkProvider returns the configured provider, or the public gateway of the chain
+func (s *ExactMultiversXScheme) networkProvider(chainID string) (multiversx.NetworkProvider, error) {
+	if s.provider != nil {
+		return s.provider, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p, ok := s.gateways[chainID]; ok {
+		return p, nil
+	}
+	apiUrl, ok := multiversx.DefaultGatewayURL(chainID)
+	if !ok {
+		return nil, fmt.Errorf("no network provider configured for chain %s: use WithNetworkProvider", chainID)
+	}
+	p := multiversx.NewGatewayProvider(apiUrl, nil)
+	s.gateways[chainID] = p
+	return p, nil
+}
@@ -2,11 +2,19 @@ package client
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"x402-integration/mechanisms/multiversx"
+	"x402-integration/mechanisms/multiversx/exact/facilitator"
 
+	x402 "github.com/coinbase/x402/go"
 	"github.com/coinbase/x402/go/types"
 )
 
@@ -40,6 +48,20 @@ func (m *MockNetworkProvider) GetNonce(ctx context.Context, address string) (uin
 	return m.nonce, m.err
 }
 
+func (m *MockNetworkProvider) GetAccount(ctx context.Context, address string) (multiversx.Account, error) {
+	return multiversx.Account{Address: address, Nonce: m.nonce, Balance: "0"}, m.err
+}
+
+func (m *MockNetworkProvider) GetNetworkConfig(ctx context.Context) (multiversx.NetworkParameters, error) {
+	return multiversx.NetworkParameters{
+		ChainID:          "D",
+		MinGasLimit:      50000,
+		GasPerDataByte:   1500,
+		MinGasPrice:      1000000000,
+		GasPriceModifier: 0.01,
+	}, m.err
+}
+
 func TestCreatePaymentPayload_EGLD(t *testing.T) {
 	signer := &MockSigner{addr: testSender}
 	mockProvider := &MockNetworkProvider{nonce: 15}
@@ -118,6 +140,145 @@ func TestCreatePaymentPayload_ESDT(t *testing.T) {
 	}
 }
 
+func TestCreatePaymentPayload_ESDT_DirectTransfer(t *testing.T) {
+	signer := &MockSigner{addr: testSender}
+	mockProvider := &MockNetworkProvider{nonce: 21}
+	scheme := NewExactMultiversXScheme(signer,
+		WithNetworkProvider(mockProvider),
+		WithTransferFormat(multiversx.TransferFormatESDTTransfer),
+	)
+
+	req := types.PaymentRequirements{
+		PayTo:   testPayTo,
+		Amount:  "100",
+		Asset:   testAsset,
+		Network: "multiversx:D",
+	}
+
+	payload, err := scheme.CreatePaymentPayload(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to create payload: %v", err)
+	}
+
+	dataBytes, _ := json.Marshal(payload.Payload)
+	var rp multiversx.ExactRelayedPayload
+	json.Unmarshal(dataBytes, &rp)
+
+	// Direct transfer: sent to PayTo, token and amount only in the data
+	if rp.Data.Receiver != testPayTo {
+		t.Errorf("ESDTTransfer receiver should be PayTo, got %s", rp.Data.Receiver)
+	}
+	if rp.Data.Value != "0" {
+		t.Errorf("ESDT tx value should be 0 EGLD, got %s", rp.Data.Value)
+	}
+	expected := "ESDTTransfer@" + hex.EncodeToString([]byte(testAsset)) + "@64"
+	if rp.Data.Data != expected {
+		t.Errorf("Expected data %s, got %s", expected, rp.Data.Data)
+	}
+}
+
+func TestCreatePaymentPayload_NFT(t *testing.T) {
+	signer := &MockSigner{addr: testSender}
+	mockProvider := &MockNetworkProvider{nonce: 22}
+	collectionHex := hex.EncodeToString([]byte("TICKET-abcdef"))
+	payToHex := "8049d639e5a6980d1cd2392abcce41029cda74a1563523a202f09641cc2618f8"
+
+	req := types.PaymentRequirements{
+		PayTo:   testPayTo,
+		Amount:  "2",
+		Asset:   "TICKET-abcdef-0a",
+		Network: "multiversx:D",
+	}
+
+	tests := []struct {
+		format   multiversx.TransferFormat
+		expected string
+	}{
+		{multiversx.TransferFormatMultiESDT, "MultiESDTNFTTransfer@" + payToHex + "@01@" + collectionHex + "@0a@02"},
+		{multiversx.TransferFormatESDTTransfer, "ESDTNFTTransfer@" + collectionHex + "@0a@02@" + payToHex},
+	}
+	for _, tc := range tests {
+		scheme := NewExactMultiversXScheme(signer, WithNetworkProvider(mockProvider), WithTransferFormat(tc.format))
+		payload, err := scheme.CreatePaymentPayload(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Failed to create payload: %v", err)
+		}
+
+		dataBytes, _ := json.Marshal(payload.Payload)
+		var rp multiversx.ExactRelayedPayload
+		json.Unmarshal(dataBytes, &rp)
+
+		// The nonce travels in the transfer, the identifier is the collection
+		if rp.Data.Data != tc.expected {
+			t.Errorf("Expected data %s, got %s", tc.expected, rp.Data.Data)
+		}
+		if rp.Data.Receiver != testSender {
+			t.Errorf("NFT transfers are self-transfers, got receiver %s", rp.Data.Receiver)
+		}
+	}
+
+	req.Asset = "TICKET-abcdef-00"
+	scheme := NewExactMultiversXScheme(signer, WithNetworkProvider(mockProvider))
+	if _, err := scheme.CreatePaymentPayload(context.Background(), req); err == nil {
+		t.Error("Expected an error for a zero token nonce")
+	}
+}
+
+func TestCreatePaymentPayload_Bundle(t *testing.T) {
+	signer := &MockSigner{addr: testSender}
+	mockProvider := &MockNetworkProvider{nonce: 23}
+	payToHex := "8049d639e5a6980d1cd2392abcce41029cda74a1563523a202f09641cc2618f8"
+
+	// USDC fee plus one loyalty point and some EGLD, as decoded from JSON
+	req := types.PaymentRequirements{
+		PayTo:   testPayTo,
+		Amount:  "100",
+		Asset:   testAsset,
+		Network: "multiversx:D",
+		Extra: map[string]interface{}{
+			multiversx.ExtraKeyAssets: []interface{}{
+				map[string]interface{}{"asset": "LOYAL-abcdef", "amount": "1"},
+				map[string]interface{}{"asset": "EGLD", "amount": "1000"},
+			},
+		},
+	}
+
+	// The preferred format cannot express a bundle: it is always a MultiESDTNFTTransfer
+	scheme := NewExactMultiversXScheme(signer,
+		WithNetworkProvider(mockProvider),
+		WithTransferFormat(multiversx.TransferFormatESDTTransfer),
+	)
+	payload, err := scheme.CreatePaymentPayload(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to create payload: %v", err)
+	}
+
+	dataBytes, _ := json.Marshal(payload.Payload)
+	var rp multiversx.ExactRelayedPayload
+	json.Unmarshal(dataBytes, &rp)
+
+	expected := "MultiESDTNFTTransfer@" + payToHex + "@03" +
+		"@" + hex.EncodeToString([]byte(testAsset)) + "@00@64" +
+		"@" + hex.EncodeToString([]byte("LOYAL-abcdef")) + "@00@01" +
+		"@" + hex.EncodeToString([]byte(multiversx.EGLDTokenIdentifier)) + "@00@03e8"
+	if rp.Data.Data != expected {
+		t.Errorf("Expected data\n%s\ngot\n%s", expected, rp.Data.Data)
+	}
+	if rp.Data.Receiver != testSender || rp.Data.Value != "0" {
+		t.Errorf("Bundle must be a zero-value self-transfer, got receiver %s value %s", rp.Data.Receiver, rp.Data.Value)
+	}
+
+	// Malformed entries are rejected instead of silently dropped
+	req.Extra[multiversx.ExtraKeyAssets] = []interface{}{map[string]interface{}{"asset": "LOYAL-abcdef", "amount": "0"}}
+	if _, err := scheme.CreatePaymentPayload(context.Background(), req); err == nil {
+		t.Error("Expected an error for a zero amount leg")
+	}
+	req.Extra[multiversx.ExtraKeyAssets] = []interface{}{map[string]interface{}{"asset": testAsset, "amount": "5"}}
+	if _, err := scheme.CreatePaymentPayload(context.Background(), req); err == nil {
+		t.Error("Expected an error for an asset listed twice")
+	}
+}
+
 func TestCreatePaymentPayload_ESDT_WithResourceID(t *testing.T) {
 	signer := &MockSigner{addr: testSender}
 	mockProvider := &MockNetworkProvider{nonce: 25}
@@ -150,6 +311,54 @@ func TestCreatePaymentPayload_ESDT_WithResourceID(t *testing.T) {
 	}
 }
 
+func TestCreatePaymentPayload_EGLD_WithResourceID(t *testing.T) {
+	signer := &MockSigner{addr: testSender}
+	mockProvider := &MockNetworkProvider{nonce: 26}
+	scheme := NewExactMultiversXScheme(signer, WithNetworkProvider(mockProvider))
+
+	req := types.PaymentRequirements{
+		PayTo:   testPayTo,
+		Amount:  "100",
+		Asset:   "EGLD",
+		Network: "multiversx:D",
+		Extra: map[string]interface{}{
+			multiversx.ExtraKeyResourceID: "inv_123",
+		},
+	}
+
+	payload, err := scheme.CreatePaymentPayload(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to create payload: %v", err)
+	}
+
+	dataBytes, _ := json.Marshal(payload.Payload)
+	var rp multiversx.ExactRelayedPayload
+	json.Unmarshal(dataBytes, &rp)
+
+	// Plain transfer: the resourceId is the data field, as the TypeScript signer writes it
+	if rp.Data.Data != "inv_123" {
+		t.Errorf("Expected data inv_123, got %q", rp.Data.Data)
+	}
+
+	// The expiry is signed along: resourceId@hex(validUntil)
+	req.Extra[multiversx.ExtraKeyValidUntil] = float64(1700000000)
+	payload, err = scheme.CreatePaymentPayload(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to create payload: %v", err)
+	}
+	dataBytes, _ = json.Marshal(payload.Payload)
+	json.Unmarshal(dataBytes, &rp)
+	if rp.Data.Data != "inv_123@6553f100" {
+		t.Errorf("Expected data inv_123@6553f100, got %q", rp.Data.Data)
+	}
+	delete(req.Extra, multiversx.ExtraKeyValidUntil)
+
+	req.Extra[multiversx.ExtraKeyResourceID] = "inv@123"
+	if _, err := scheme.CreatePaymentPayload(context.Background(), req); err == nil {
+		t.Error("Expected an error for a resourceId containing a separator")
+	}
+}
+
 func TestCreatePaymentPayload_EGLD_Alias(t *testing.T) {
 	signer := &MockSigner{addr: testSender}
 	mockProvider := &MockNetworkProvider{nonce: 30}
@@ -191,3 +400,287 @@ func TestCreatePaymentPayload_EGLD_Alias(t *testing.T) {
 		t.Errorf("Data should contain EGLD-000000 hex %s, got %s", tokenHex, rp.Data.Data)
 	}
 }
+
+func TestCreatePaymentPayload_Relayed(t *testing.T) {
+	signer := &MockSigner{addr: testSender}
+	mockProvider := &MockNetworkProvider{nonce: 35}
+	scheme := NewExactMultiversXScheme(signer, WithNetworkProvider(mockProvider))
+
+	relayer := "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+	req := types.PaymentRequirements{
+		PayTo:   testPayTo,
+		Amount:  "100",
+		Asset:   "EGLD",
+		Network: "multiversx:D",
+		Extra: map[string]interface{}{
+			multiversx.ExtraKeyRelayer: relayer,
+		},
+	}
+
+	payload, err := scheme.CreatePaymentPayload(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to create payload: %v", err)
+	}
+
+	dataBytes, _ := json.Marshal(payload.Payload)
+	var rp multiversx.ExactRelayedPayload
+	json.Unmarshal(dataBytes, &rp)
+
+	if rp.Data.Relayer != relayer {
+		t.Errorf("Wrong relayer: %s", rp.Data.Relayer)
+	}
+	if rp.Data.Version != multiversx.RelayedTransactionVersion {
+		t.Errorf("Relayed payload must use version %d, got %d", multiversx.RelayedTransactionVersion, rp.Data.Version)
+	}
+	if rp.Data.GasLimit != 50000+multiversx.RelayerGasOverhead {
+		t.Errorf("Relayed payload must include relayer gas, got %d", rp.Data.GasLimit)
+	}
+	if rp.Data.RelayerSignature != "" {
+		t.Errorf("Client must not fill the relayer signature, got %s", rp.Data.RelayerSignature)
+	}
+}
+
+func TestCreatePaymentPayload_NonceProviderError(t *testing.T) {
+	signer := &MockSigner{addr: testSender}
+	mockProvider := &MockNetworkProvider{err: errors.New("gateway unavailable")}
+	scheme := NewExactMultiversXScheme(signer, WithNetworkProvider(mockProvider))
+
+	req := types.PaymentRequirements{
+		PayTo:   testPayTo,
+		Amount:  "100",
+		Asset:   "EGLD",
+		Network: "multiversx:D",
+	}
+
+	if _, err := scheme.CreatePaymentPayload(context.Background(), req); err == nil {
+		t.Error("Expected error when the nonce cannot be fetched")
+	}
+}
+
+func TestCreatePaymentPayload_CustomChainRequiresProvider(t *testing.T) {
+	signer := &MockSigner{addr: testSender}
+	scheme := NewExactMultiversXScheme(signer)
+
+	req := types.PaymentRequirements{
+		PayTo:   testPayTo,
+		Amount:  "100",
+		Asset:   "EGLD",
+		Network: "multiversx:local-testnet",
+	}
+
+	_, err := scheme.CreatePaymentPayload(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "WithNetworkProvider") {
+		t.Errorf("Expected missing provider error, got %v", err)
+	}
+}
+
+func decodeNonce(t *testing.T, payload types.PaymentPayload) uint64 {
+	t.Helper()
+	dataBytes, _ := json.Marshal(payload.Payload)
+	var rp multiversx.ExactRelayedPayload
+	if err := json.Unmarshal(dataBytes, &rp); err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+	return rp.Data.Nonce
+}
+
+func TestCreatePaymentPayload_ConcurrentNonces(t *testing.T) {
+	signer := &MockSigner{addr: testSender}
+	mockProvider := &MockNetworkProvider{nonce: 40}
+	scheme := NewExactMultiversXScheme(signer, WithNetworkProvider(mockProvider))
+
+	req := types.PaymentRequirements{
+		PayTo:   testPayTo,
+		Amount:  "100",
+		Asset:   "EGLD",
+		Network: "multiversx:D",
+	}
+
+	const payments = 300
+	payloads := make([]types.PaymentPayload, payments)
+	var wg sync.WaitGroup
+	for i := 0; i < payments; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			payload, err := scheme.CreatePaymentPayload(context.Background(), req)
+			if err != nil {
+				t.Errorf("Failed to create payload: %v", err)
+				return
+			}
+			payloads[i] = payload
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[uint64]bool)
+	for _, payload := range payloads {
+		nonce := decodeNonce(t, payload)
+		if seen[nonce] {
+			t.Fatalf("Duplicate nonce %d", nonce)
+		}
+		seen[nonce] = true
+	}
+	for n := uint64(40); n < 40+payments; n++ {
+		if !seen[n] {
+			t.Errorf("Nonce %d was skipped", n)
+		}
+	}
+}
+
+func TestCreatePaymentPayload_AbandonAndSettleResponse(t *testing.T) {
+	signer := &MockSigner{addr: testSender}
+	mockProvider := &MockNetworkProvider{nonce: 50}
+	scheme := NewExactMultiversXScheme(signer, WithNetworkProvider(mockProvider))
+	ctx := context.Background()
+
+	req := types.PaymentRequirements{
+		PayTo:   testPayTo,
+		Amount:  "100",
+		Asset:   "EGLD",
+		Network: "multiversx:D",
+	}
+
+	first, _ := scheme.CreatePaymentPayload(ctx, req)
+	second, _ := scheme.CreatePaymentPayload(ctx, req)
+	if decodeNonce(t, first) != 50 || decodeNonce(t, second) != 51 {
+		t.Fatalf("Expected nonces 50 and 51")
+	}
+
+	// Abandoned payment: its nonce is handed out again
+	if err := scheme.AbandonPayment(first); err != nil {
+		t.Fatalf("AbandonPayment: %v", err)
+	}
+	retry, _ := scheme.CreatePaymentPayload(ctx, req)
+	if n := decodeNonce(t, retry); n != 50 {
+		t.Errorf("Expected abandoned nonce 50 to be reused, got %d", n)
+	}
+
+	// Broadcast but failed on-chain: the nonce is consumed
+	scheme.HandleSettleResponse(ctx, second, &x402.SettleResponse{
+		Success:     false,
+		ErrorReason: multiversx.FormatReason(multiversx.SettleReasonTransactionFailed, "execution failed"),
+	})
+	next, _ := scheme.CreatePaymentPayload(ctx, req)
+	if n := decodeNonce(t, next); n != 52 {
+		t.Errorf("Expected 52, got %d", n)
+	}
+
+	// Nonce error: resync from the network
+	mockProvider.nonce = 90
+	err := scheme.HandleSettleResponse(ctx, next, &x402.SettleResponse{
+		Success:     false,
+		ErrorReason: multiversx.FormatReason(multiversx.SettleReasonNonceTooLow, "lower nonce in transaction"),
+	})
+	if err != nil {
+		t.Fatalf("HandleSettleResponse: %v", err)
+	}
+	resynced, _ := scheme.CreatePaymentPayload(ctx, req)
+	if n := decodeNonce(t, resynced); n != 90 {
+		t.Errorf("Expected resynced nonce 90, got %d", n)
+	}
+}
+
+func TestCreatePaymentPayload_GasEstimation(t *testing.T) {
+	signer := &MockSigner{addr: testSender}
+	scheme := NewExactMultiversXScheme(signer, WithNetworkProvider(&MockNetworkProvider{nonce: 60}))
+
+	req := types.PaymentRequirements{
+		PayTo:   testPayTo,
+		Amount:  "100",
+		Asset:   testAsset,
+		Network: "multiversx:D",
+	}
+
+	payload, err := scheme.CreatePaymentPayload(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to create payload: %v", err)
+	}
+
+	dataBytes, _ := json.Marshal(payload.Payload)
+	var rp multiversx.ExactRelayedPayload
+	json.Unmarshal(dataBytes, &rp)
+
+	// Move balance + data bytes + one MultiESDTNFTTransfer, far below the old fixed 60M
+	expected := uint64(50000 + 1500*len(rp.Data.Data) + 200000 + 800000)
+	if rp.Data.GasLimit != expected {
+		t.Errorf("Expected gas limit %d, got %d", expected, rp.Data.GasLimit)
+	}
+	if rp.Data.GasPrice != 1000000000 {
+		t.Errorf("Expected min gas price, got %d", rp.Data.GasPrice)
+	}
+}
+
+// ed25519Signer signs as Alice, so the facilitator can check the signature
+type ed25519Signer struct{}
+
+const (
+	aliceAddress   = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+	aliceSecretHex = "413f42575f7f26fad3317a778771212fdb80245850981e48b58a4f25e344e8f9"
+)
+
+func (ed25519Signer) Address() string {
+	return aliceAddress
+}
+func (ed25519Signer) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	seed, _ := hex.DecodeString(aliceSecretHex)
+	return ed25519.Sign(ed25519.NewKeyFromSeed(seed), message), nil
+}
+
+func TestCreatePaymentPayload_NetworkAliases(t *testing.T) {
+	gateway := httptest.NewServer(http.NotFoundHandler())
+	defer gateway.Close()
+
+	tests := []struct {
+		network string
+		chainID string
+	}{
+		{"mainnet", "1"},
+		{"testnet", "T"},
+		{"devnet", "D"},
+		{"multiversx:1", "1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.network, func(t *testing.T) {
+			scheme := NewExactMultiversXScheme(ed25519Signer{}, WithNetworkProvider(&MockNetworkProvider{nonce: 7}))
+			req := types.PaymentRequirements{
+				Scheme:  multiversx.SchemeExact,
+				PayTo:   testPayTo,
+				Amount:  "100",
+				Asset:   "EGLD",
+				Network: tc.network,
+			}
+
+			payload, err := scheme.CreatePaymentPayload(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Failed to create payload: %v", err)
+			}
+			tx, err := decodeTransaction(payload)
+			if err != nil {
+				t.Fatalf("Failed to decode payload: %v", err)
+			}
+			if tx.ChainID != tc.chainID {
+				t.Errorf("Expected chain %s, got %s", tc.chainID, tx.ChainID)
+			}
+
+			verifier := facilitator.NewExactMultiversXScheme(gateway.URL, facilitator.WithSimulation(false))
+			resp, err := verifier.Verify(context.Background(), payload, req)
+			if err != nil {
+				t.Fatalf("Verify failed: %v", err)
+			}
+			if !resp.IsValid {
+				t.Errorf("Payment for %s rejected: %s", tc.network, resp.InvalidReason)
+			}
+		})
+	}
+}
+
+func TestCreatePaymentPayload_UnsupportedNetwork(t *testing.T) {
+	scheme := NewExactMultiversXScheme(&MockSigner{addr: testSender}, WithNetworkProvider(&MockNetworkProvider{}))
+	req := types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD", Network: "eip155:1"}
+
+	if _, err := scheme.CreatePaymentPayload(context.Background(), req); err == nil {
+		t.Error("Expected an error for a network that is not MultiversX")
+	}
+}
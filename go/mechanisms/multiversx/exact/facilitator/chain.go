@@ -0,0 +1,69 @@
+package facilitator
+
+import (
+	"context"
+	"fmt"
+
+	"x402-integration/mechanisms/multiversx"
+
+	"github.com/coinbase/x402/go/types"
+)
+
+// WithChainID declares the chain the gateway is expected to serve. CheckGateway fails
+// if the gateway reports another one, and Verify rejects payloads for other chains.
+func WithChainID(chainID string) Option {
+	return func(s *ExactMultiversXScheme) {
+		s.config.ChainID = chainID
+	}
+}
+
+// CheckGateway fetches the gateway's chain ID and caches it for Verify. Call it at startup
+// to catch an API URL pointing at the wrong network before any payment is accepted.
+func (s *ExactMultiversXScheme) CheckGateway(ctx context.Context) error {
+	params, err := s.network.GetNetworkConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query gateway %s: %v", s.config.APIUrl, err)
+	}
+	if params.ChainID == "" {
+		return fmt.Errorf("gateway %s did not report a chain ID", s.config.APIUrl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.config.ChainID != "" && s.config.ChainID != params.ChainID {
+		return fmt.Errorf("gateway %s serves chain %s, expected %s", s.config.APIUrl, params.ChainID, s.config.ChainID)
+	}
+	s.config.ChainID = params.ChainID
+	return nil
+}
+
+// ChainID returns the chain served by the gateway, empty until known (WithChainID or CheckGateway)
+func (s *ExactMultiversXScheme) ChainID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config.ChainID
+}
+
+// checkChain derives the expected chain from requirements.Network (falling back to the gateway's
+// chain) and rejects payloads signed for another chain. A requirement for a chain our gateway
+// does not serve is a configuration error, not a bad payment.
+func (s *ExactMultiversXScheme) checkChain(txData multiversx.Transaction, requirements types.PaymentRequirements) error {
+	gatewayChain := s.ChainID()
+
+	expected := gatewayChain
+	if requirements.Network != "" {
+		chainID, err := multiversx.GetMultiversXChainId(string(requirements.Network))
+		if err != nil {
+			return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidRequirements, "%v", err)
+		}
+		if gatewayChain != "" && chainID != gatewayChain {
+			return fmt.Errorf("requirements are for chain %s but gateway %s serves chain %s", chainID, s.config.APIUrl, gatewayChain)
+		}
+		expected = chainID
+	}
+
+	if expected != "" && txData.ChainID != expected {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonChainMismatch, "expected chain %s, got %s", expected, txData.ChainID)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package facilitator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"x402-integration/mechanisms/multiversx"
+
+	x402 "github.com/coinbase/x402/go"
+	"github.com/coinbase/x402/go/types"
+)
+
+// settleWithFinality polls the gateway until the broadcast transaction reaches a final state
+// or the deadline derived from requirements.MaxTimeoutSeconds expires.
+func (s *ExactMultiversXScheme) settleWithFinality(ctx context.Context, txHash string, payer string, requirements types.PaymentRequirements) (*x402.SettleResponse, error) {
+	network := x402.Network(requirements.Network)
+
+	timeout := defaultFinalityTimeout
+	if requirements.MaxTimeoutSeconds > 0 {
+		timeout = time.Duration(requirements.MaxTimeoutSeconds) * time.Second
+	}
+	pollCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	tx, err := s.waitForFinality(pollCtx, txHash)
+	if err != nil {
+		// Caller went away: not a payment outcome
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return &x402.SettleResponse{
+			Success:     false,
+			ErrorReason: multiversx.FormatReason(multiversx.SettleReasonFinalityTimeout, err.Error()),
+			Transaction: txHash,
+			Payer:       payer,
+			Network:     network,
+		}, nil
+	}
+
+	// A "success" status does not cover failed ESDT transfers or cross-shard legs: those only show in the logs and results
+	detail := tx.FailureReason()
+	if detail == "" && !multiversx.IsSuccessfulStatus(tx.Status) {
+		detail = fmt.Sprintf("status %s", tx.Status)
+	}
+	if detail != "" {
+		return &x402.SettleResponse{
+			Success:     false,
+			ErrorReason: multiversx.FormatReason(multiversx.SettleReasonTransactionFailed, detail),
+			Transaction: txHash,
+			Payer:       payer,
+			Network:     network,
+		}, nil
+	}
+
+	return &x402.SettleResponse{
+		Success:     true,
+		Transaction: txHash,
+		Payer:       payer,
+		Network:     network,
+	}, nil
+}
+
+// waitForFinality returns the executed transaction once its status is final and,
+// for cross-shard transfers, once the destination shard executed it.
+// Transient gateway errors are retried until ctx expires.
+func (s *ExactMultiversXScheme) waitForFinality(ctx context.Context, txHash string) (*multiversx.TransactionOnNetwork, error) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		tx, err := s.checkFinality(ctx, txHash)
+		if err != nil {
+			lastErr = err
+		} else if tx != nil {
+			return tx, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("transaction %s not final before deadline: %v", txHash, lastErr)
+			}
+			return nil, fmt.Errorf("transaction %s not final before deadline", txHash)
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkFinality returns nil, nil while the transaction is still in progress
+func (s *ExactMultiversXScheme) checkFinality(ctx context.Context, txHash string) (*multiversx.TransactionOnNetwork, error) {
+	var statusResp multiversx.TransactionStatusResponse
+	if err := s.getJSON(ctx, fmt.Sprintf("/transaction/%s/status", url.PathEscape(txHash)), &statusResp); err != nil {
+		return nil, err
+	}
+	if statusResp.Error != "" {
+		return nil, fmt.Errorf("status API returned error: %s", statusResp.Error)
+	}
+	if !multiversx.IsFinalStatus(statusResp.Data.Status) {
+		return nil, nil
+	}
+
+	var txResp multiversx.TransactionResponse
+	if err := s.getJSON(ctx, fmt.Sprintf("/transaction/%s?withResults=true", url.PathEscape(txHash)), &txResp); err != nil {
+		return nil, err
+	}
+	if txResp.Error != "" {
+		return nil, fmt.Errorf("transaction API returned error: %s", txResp.Error)
+	}
+
+	// The processed status decides the outcome, the transaction's own status may lag behind it
+	tx := txResp.Data.Transaction
+	tx.Status = statusResp.Data.Status
+	if multiversx.IsSuccessfulStatus(tx.Status) && !tx.IsExecutedAtDestination() {
+		return nil, nil
+	}
+
+	return &tx, nil
+}
+
+func (s *ExactMultiversXScheme) getJSON(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.config.APIUrl+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %v", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package facilitator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"x402-integration/mechanisms/multiversx"
+)
+
+// WithGasPolicy caps the gas our relayer pays for Relayed V3 payloads.
+// Payloads whose sender pays the gas are not subject to the policy.
+// With a relayer and no policy, multiversx.DefaultGasPolicy applies.
+func WithGasPolicy(policy multiversx.GasPolicy) Option {
+	return func(s *ExactMultiversXScheme) {
+		s.gasPolicy = &policy
+		s.gasPolicyDisabled = false
+		s.feeWindow = nil
+		if policy.MaxFeePerWindow != nil && policy.Window > 0 {
+			s.feeWindow = &feeWindow{max: policy.MaxFeePerWindow, window: policy.Window, now: time.Now}
+		}
+	}
+}
+
+// WithoutGasPolicy lets the relayer pay whatever gas payloads ask for. Only use it when
+// another layer caps relayed gas: by default a relayer is bound by multiversx.DefaultGasPolicy.
+func WithoutGasPolicy() Option {
+	return func(s *ExactMultiversXScheme) {
+		s.gasPolicy = nil
+		s.gasPolicyDisabled = true
+		s.feeWindow = nil
+	}
+}
+
+// applyDefaultGasPolicy caps a configured relayer when no policy was chosen
+func (s *ExactMultiversXScheme) applyDefaultGasPolicy() {
+	if s.relayer != nil && s.gasPolicy == nil && !s.gasPolicyDisabled {
+		policy := multiversx.DefaultGasPolicy()
+		s.gasPolicy = &policy
+	}
+}
+
+// feeWindow tracks the fees our relayer paid over a sliding time window
+type feeWindow struct {
+	max    *big.Int
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries []*feeEntry
+}
+
+type feeEntry struct {
+	at  time.Time
+	fee *big.Int
+}
+
+// spentLocked returns the fees inside the window, dropping older entries. Requires w.mu.
+func (w *feeWindow) spentLocked() *big.Int {
+	cutoff := w.now().Add(-w.window)
+	kept := w.entries[:0]
+	total := new(big.Int)
+	for _, e := range w.entries {
+		if e.at.After(cutoff) {
+			kept = append(kept, e)
+			total.Add(total, e.fee)
+		}
+	}
+	w.entries = kept
+	return total
+}
+
+// check reports an error if paying fee now would exceed the window budget
+func (w *feeWindow) check(fee *big.Int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.checkLocked(fee)
+}
+
+func (w *feeWindow) checkLocked(fee *big.Int) error {
+	spent := w.spentLocked()
+	if new(big.Int).Add(spent, fee).Cmp(w.max) > 0 {
+		return fmt.Errorf("fee %s exceeds remaining budget %s of %s per %s", fee, new(big.Int).Sub(w.max, spent), w.max, w.window)
+	}
+	return nil
+}
+
+// reserve atomically checks and books fee. The returned function cancels the booking.
+func (w *feeWindow) reserve(fee *big.Int) (func(), error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.checkLocked(fee); err != nil {
+		return nil, err
+	}
+	entry := &feeEntry{at: w.now(), fee: new(big.Int).Set(fee)}
+	w.entries = append(w.entries, entry)
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		for i, e := range w.entries {
+			if e == entry {
+				w.entries = append(w.entries[:i], w.entries[i+1:]...)
+				return
+			}
+		}
+	}, nil
+}
+
+// checkGasPolicy validates a relayed payload against the gas policy and returns the fee
+// our relayer would pay (nil when the policy does not apply).
+func (s *ExactMultiversXScheme) checkGasPolicy(ctx context.Context, payload multiversx.ExactRelayedPayload) (*big.Int, error) {
+	if s.gasPolicy == nil || !payload.Data.IsRelayed() {
+		return nil, nil
+	}
+
+	params, err := s.network.GetNetworkConfig(ctx)
+	if err != nil || params.MinGasLimit == 0 {
+		// The policy must hold during gateway hiccups: judge against the protocol defaults
+		params = multiversx.DefaultNetworkParameters(payload.Data.ChainID)
+	}
+
+	fee, err := s.gasPolicy.Check(params, payload.Data)
+	if err != nil {
+		return nil, multiversx.NewVerificationError(multiversx.InvalidReasonGasPolicy, "%v", err)
+	}
+	if s.feeWindow != nil {
+		if err := s.feeWindow.check(fee); err != nil {
+			return nil, multiversx.NewVerificationError(multiversx.InvalidReasonGasPolicy, "%v", err)
+		}
+	}
+	return fee, nil
+}
+
+// reserveRelayerFee books the relayer fee of a payment about to be broadcast.
+// The returned function releases it if the broadcast fails.
+func (s *ExactMultiversXScheme) reserveRelayerFee(ctx context.Context, payload multiversx.ExactRelayedPayload) (func(), error) {
+	fee, err := s.checkGasPolicy(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	if s.feeWindow == nil || fee == nil {
+		return func() {}, nil
+	}
+	release, err := s.feeWindow.reserve(fee)
+	if err != nil {
+		return nil, multiversx.NewVerificationError(multiversx.InvalidReasonGasPolicy, "%v", err)
+	}
+	return release, nil
+}
@@ -0,0 +1,93 @@
+package facilitator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"x402-integration/mechanisms/multiversx"
+
+	"github.com/coinbase/x402/go/types"
+)
+
+// WithPaymentLedger records every accepted payment (sender, nonce, transaction hash, resourceId)
+// so a signed payload cannot pay for several resources or be settled twice
+func WithPaymentLedger(ledger multiversx.PaymentLedger) Option {
+	return func(s *ExactMultiversXScheme) {
+		s.ledger = ledger
+	}
+}
+
+// recordPayment claims the ledger slot of a verified transaction
+func (s *ExactMultiversXScheme) recordPayment(tx multiversx.Transaction, requirements types.PaymentRequirements) error {
+	if s.ledger == nil {
+		return nil
+	}
+	hash, err := tx.Hash()
+	if err != nil {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidPayload, "failed to hash transaction: %v", err)
+	}
+	resourceID, _ := requirements.Extra[multiversx.ExtraKeyResourceID].(string)
+	return s.ledger.Record(multiversx.LedgerEntry{
+		ChainID:    tx.ChainID,
+		Sender:     tx.Sender,
+		Nonce:      tx.Nonce,
+		TxHash:     hash,
+		ResourceID: resourceID,
+	})
+}
+
+// checkAccountNonce rejects a transaction whose nonce the chain has passed. The simulation does
+// this when enabled; without it, a payload whose ledger entry PruneLedger dropped would verify again.
+func (s *ExactMultiversXScheme) checkAccountNonce(ctx context.Context, tx multiversx.Transaction) error {
+	if s.ledger == nil || s.simulate {
+		return nil
+	}
+	nonce, err := s.network.GetNonce(ctx, tx.Sender)
+	if err != nil {
+		return fmt.Errorf("failed to read the nonce of %s: %v", tx.Sender, err)
+	}
+	if tx.Nonce < nonce {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonNonceStale, "nonce %d already used, account nonce is %d", tx.Nonce, nonce)
+	}
+	return nil
+}
+
+// markSettled closes the ledger slot of a broadcast transaction under its on-chain hash,
+// which differs from the verified one once a relayer co-signed
+func (s *ExactMultiversXScheme) markSettled(tx multiversx.Transaction, txHash string) error {
+	if s.ledger == nil {
+		return nil
+	}
+	return s.ledger.MarkSettled(tx.ChainID, tx.Sender, tx.Nonce, txHash)
+}
+
+// PruneLedger drops the ledger entries whose nonce the chain has passed, reading the
+// account nonces of this facilitator's chain from the gateway. Run it periodically.
+// Payloads with those nonces stay rejected: by the simulation, or with WithSimulation(false)
+// by a check of the sender's account nonce, which Verify then makes on every payment.
+func (s *ExactMultiversXScheme) PruneLedger(ctx context.Context) error {
+	if s.ledger == nil {
+		return nil
+	}
+	chainID := s.ChainID()
+	if chainID == "" {
+		return fmt.Errorf("chain ID of gateway %s unknown: call CheckGateway or use WithChainID", s.config.APIUrl)
+	}
+
+	var errs []error
+	for _, account := range s.ledger.Accounts() {
+		if account.ChainID != chainID {
+			continue
+		}
+		nonce, err := s.network.GetNonce(ctx, account.Sender)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", account.Sender, err))
+			continue
+		}
+		if err := s.ledger.Prune(chainID, account.Sender, nonce); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,70 @@
+package facilitator
+
+import (
+	"net/http"
+	"time"
+
+	"x402-integration/mechanisms/multiversx"
+)
+
+// SettlementMode controls when Settle reports a payment as settled
+type SettlementMode int
+
+const (
+	// SettlementModeBroadcast settles as soon as the gateway accepts the transaction into the mempool
+	SettlementModeBroadcast SettlementMode = iota
+	// SettlementModeFinality settles only once the transaction executed on-chain
+	// (on the destination shard for cross-shard transfers)
+	SettlementModeFinality
+)
+
+const (
+	defaultPollInterval    = time.Second
+	defaultFinalityTimeout = 60 * time.Second
+)
+
+// Option configures the facilitator scheme
+type Option func(*ExactMultiversXScheme)
+
+// WithSettlementMode selects between broadcast-only and wait-for-finality settlement
+func WithSettlementMode(mode SettlementMode) Option {
+	return func(s *ExactMultiversXScheme) {
+		s.settlementMode = mode
+	}
+}
+
+// WithPollInterval sets how often transaction status is polled in SettlementModeFinality
+func WithPollInterval(interval time.Duration) Option {
+	return func(s *ExactMultiversXScheme) {
+		if interval > 0 {
+			s.pollInterval = interval
+		}
+	}
+}
+
+// WithSimulation toggles the /transaction/simulate stage of Verify.
+// The local Ed25519 signature check always runs.
+func WithSimulation(enabled bool) Option {
+	return func(s *ExactMultiversXScheme) {
+		s.simulate = enabled
+	}
+}
+
+// WithHTTPClient replaces the HTTP client used to talk to the gateway
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *ExactMultiversXScheme) {
+		if client != nil {
+			s.client = client
+		}
+	}
+}
+
+// WithTokenRegistry sets the tokens requirements may name ("USDC" rather than USDC-c76f1f).
+// The default registry knows EGLD and the well-known stablecoins.
+func WithTokenRegistry(tokens *multiversx.TokenRegistry) Option {
+	return func(s *ExactMultiversXScheme) {
+		if tokens != nil {
+			s.tokens = tokens
+		}
+	}
+}
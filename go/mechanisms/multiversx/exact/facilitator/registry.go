@@ -0,0 +1,210 @@
+package facilitator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"x402-integration/mechanisms/multiversx"
+
+	x402 "github.com/coinbase/x402/go"
+	"github.com/coinbase/x402/go/types"
+)
+
+// MultiNetworkScheme is a facilitator for several MultiversX networks (mainnet, devnet, testnet,
+// custom chains). Each CAIP-2 network gets its own gateway, relayer wallet and gas policy;
+// Verify and Settle are routed by requirements.Network.
+type MultiNetworkScheme struct {
+	opts []Option // shared by every network (settlement mode, HTTP client, ...)
+
+	mu       sync.RWMutex
+	networks map[string]*ExactMultiversXScheme // keyed by canonical CAIP-2 network
+}
+
+// NewMultiNetworkScheme registers every network of the registry. opts apply to all networks.
+func NewMultiNetworkScheme(networks map[x402.Network]multiversx.NetworkConfig, opts ...Option) (*MultiNetworkScheme, error) {
+	m := &MultiNetworkScheme{
+		opts:     opts,
+		networks: make(map[string]*ExactMultiversXScheme),
+	}
+	for network, config := range networks {
+		if err := m.Register(network, config); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Register adds (or replaces) a network. The key must be a "multiversx:<chainID>" network.
+// An empty APIUrl selects the public gateway of mainnet, devnet or testnet.
+func (m *MultiNetworkScheme) Register(network x402.Network, config multiversx.NetworkConfig) error {
+	if !strings.HasPrefix(string(network), "multiversx:") {
+		return fmt.Errorf("network %s is not a CAIP-2 multiversx network", network)
+	}
+	chainID, err := multiversx.GetMultiversXChainId(string(network))
+	if err != nil {
+		return err
+	}
+	if chainID == "" {
+		return fmt.Errorf("network %s has no chain ID", network)
+	}
+	if config.ChainID != "" && config.ChainID != chainID {
+		return fmt.Errorf("network %s configured with chain ID %s", network, config.ChainID)
+	}
+	if config.APIUrl == "" {
+		apiUrl, ok := multiversx.DefaultGatewayURL(chainID)
+		if !ok {
+			return fmt.Errorf("network %s needs an API URL", network)
+		}
+		config.APIUrl = apiUrl
+	}
+
+	opts := append([]Option{}, m.opts...)
+	opts = append(opts, WithChainID(chainID))
+	if config.Relayer != nil {
+		opts = append(opts, WithRelayerSigner(config.Relayer))
+	}
+	if config.GasPolicy != nil {
+		opts = append(opts, WithGasPolicy(*config.GasPolicy))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.networks[multiversx.NetworkFromChainID(chainID)] = NewExactMultiversXScheme(config.APIUrl, opts...)
+	return nil
+}
+
+// Networks lists the registered networks, sorted
+func (m *MultiNetworkScheme) Networks() []x402.Network {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]string, 0, len(m.networks))
+	for k := range m.networks {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	networks := make([]x402.Network, len(keys))
+	for i, k := range keys {
+		networks[i] = x402.Network(k)
+	}
+	return networks
+}
+
+// lookup returns the facilitator of a network, accepting aliases such as "devnet"
+func (m *MultiNetworkScheme) lookup(network x402.Network) (*ExactMultiversXScheme, bool) {
+	chainID, err := multiversx.GetMultiversXChainId(string(network))
+	if err != nil {
+		return nil, false
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.networks[multiversx.NetworkFromChainID(chainID)]
+	return s, ok
+}
+
+func (m *MultiNetworkScheme) Scheme() string {
+	return multiversx.SchemeExact
+}
+
+func (m *MultiNetworkScheme) CaipFamily() string {
+	return "multiversx:*"
+}
+
+func (m *MultiNetworkScheme) GetExtra(network x402.Network) map[string]interface{} {
+	s, ok := m.lookup(network)
+	if !ok {
+		return nil
+	}
+	return s.GetExtra(network)
+}
+
+func (m *MultiNetworkScheme) GetSigners(network x402.Network) []string {
+	s, ok := m.lookup(network)
+	if !ok {
+		return []string{}
+	}
+	return s.GetSigners(network)
+}
+
+// SupportedKinds lists one kind per registered network, with its extra (relayer)
+func (m *MultiNetworkScheme) SupportedKinds() []types.SupportedKind {
+	networks := m.Networks()
+	kinds := make([]types.SupportedKind, 0, len(networks))
+	for _, network := range networks {
+		kinds = append(kinds, types.SupportedKind{
+			X402Version: 2,
+			Scheme:      multiversx.SchemeExact,
+			Network:     string(network),
+			Extra:       m.GetExtra(network),
+		})
+	}
+	return kinds
+}
+
+func (m *MultiNetworkScheme) Verify(ctx context.Context, payload types.PaymentPayload, requirements types.PaymentRequirements) (*x402.VerifyResponse, error) {
+	s, ok := m.lookup(x402.Network(requirements.Network))
+	if !ok {
+		return &x402.VerifyResponse{
+			IsValid:       false,
+			InvalidReason: multiversx.InvalidReasonUnsupportedNetwork,
+		}, nil
+	}
+	return s.Verify(ctx, payload, requirements)
+}
+
+func (m *MultiNetworkScheme) Settle(ctx context.Context, payload types.PaymentPayload, requirements types.PaymentRequirements) (*x402.SettleResponse, error) {
+	network := x402.Network(requirements.Network)
+	s, ok := m.lookup(network)
+	if !ok {
+		return &x402.SettleResponse{
+			Success:     false,
+			ErrorReason: multiversx.FormatReason(multiversx.SettleReasonUnsupportedNetwork, string(network)),
+			Network:     network,
+		}, nil
+	}
+	return s.Settle(ctx, payload, requirements)
+}
+
+// CheckGateways runs CheckGateway on every network, reporting all misconfigured gateways
+func (m *MultiNetworkScheme) CheckGateways(ctx context.Context) error {
+	var errs []error
+	for _, network := range m.Networks() {
+		s, _ := m.lookup(network)
+		if err := s.CheckGateway(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", network, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PruneLedger prunes the payment ledger on every network
+func (m *MultiNetworkScheme) PruneLedger(ctx context.Context) error {
+	var errs []error
+	for _, network := range m.Networks() {
+		s, _ := m.lookup(network)
+		if err := s.PruneLedger(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", network, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close releases the relayer wallets of every network
+func (m *MultiNetworkScheme) Close() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var errs []error
+	for _, s := range m.networks {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+package facilitator
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"x402-integration/mechanisms/multiversx"
+)
+
+// WithRelayerSigner configures the wallet used to co-sign and pay gas for Relayed V3 payloads.
+// Use multiversx.LoadPEMWallet or multiversx.LoadKeystoreWallet to load it from disk.
+func WithRelayerSigner(signer multiversx.FacilitatorMultiversXSigner) Option {
+	return func(s *ExactMultiversXScheme) {
+		s.relayer = signer
+	}
+}
+
+// checkRelayer rejects relayed payloads that name a relayer other than ours
+func (s *ExactMultiversXScheme) checkRelayer(payload multiversx.ExactRelayedPayload) error {
+	if !payload.Data.IsRelayed() {
+		return nil
+	}
+	if s.relayer == nil {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonRelayerMismatch, "relayed transactions are not supported: no relayer configured")
+	}
+	if !multiversx.AddressesEqual(payload.Data.Relayer, s.relayer.Address()) {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonRelayerMismatch, "expected %s, got %s", s.relayer.Address(), payload.Data.Relayer)
+	}
+	if payload.Data.Version < multiversx.RelayedTransactionVersion {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidPayload, "relayed transactions require version %d, got %d", multiversx.RelayedTransactionVersion, payload.Data.Version)
+	}
+	return nil
+}
+
+// cosign adds the relayer signature to a relayed payload. Non-relayed payloads are returned as is.
+func (s *ExactMultiversXScheme) cosign(ctx context.Context, payload multiversx.ExactRelayedPayload) (multiversx.ExactRelayedPayload, error) {
+	if !payload.Data.IsRelayed() {
+		return payload, nil
+	}
+	if err := s.checkRelayer(payload); err != nil {
+		return payload, err
+	}
+
+	txBytes, err := payload.Data.BytesForSigning()
+	if err != nil {
+		return payload, fmt.Errorf("failed to serialize transaction for relayer: %v", err)
+	}
+
+	sigBytes, err := s.relayer.Sign(ctx, txBytes)
+	if err != nil {
+		return payload, fmt.Errorf("relayer failed to sign: %v", err)
+	}
+
+	payload.Data.RelayerSignature = hex.EncodeToString(sigBytes)
+	return payload, nil
+}
@@ -3,14 +3,15 @@ package facilitator
 import (
 	"bytes"
 	"context"
-	"encoding/base64"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log"
 	"math/big"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"x402-integration/mechanisms/multiversx"
@@ -21,15 +22,38 @@ import (
 
 // ExactMultiversXScheme implements SchemeNetworkFacilitator
 type ExactMultiversXScheme struct {
-	config multiversx.NetworkConfig
-	client *http.Client
+	mu                sync.RWMutex // guards config.ChainID, learnt from the gateway
+	config            multiversx.NetworkConfig
+	client            *http.Client
+	settlementMode    SettlementMode
+	pollInterval      time.Duration
+	relayer           multiversx.FacilitatorMultiversXSigner
+	simulate          bool
+	network           multiversx.NetworkProvider
+	gasPolicy         *multiversx.GasPolicy
+	gasPolicyDisabled bool // WithoutGasPolicy: no default policy for the relayer
+	feeWindow         *feeWindow
+	ledger            multiversx.PaymentLedger
+	tokens            *multiversx.TokenRegistry
+	now               func() time.Time
 }
 
-func NewExactMultiversXScheme(apiUrl string) *ExactMultiversXScheme {
-	return &ExactMultiversXScheme{
-		config: multiversx.NetworkConfig{APIUrl: apiUrl},
-		client: &http.Client{Timeout: 10 * time.Second},
+func NewExactMultiversXScheme(apiUrl string, opts ...Option) *ExactMultiversXScheme {
+	s := &ExactMultiversXScheme{
+		config:         multiversx.NetworkConfig{APIUrl: apiUrl},
+		client:         &http.Client{Timeout: 10 * time.Second},
+		settlementMode: SettlementModeBroadcast,
+		pollInterval:   defaultPollInterval,
+		simulate:       true,
+		tokens:         multiversx.NewDefaultTokenRegistry(),
+		now:            time.Now,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.applyDefaultGasPolicy()
+	s.network = multiversx.NewGatewayProvider(apiUrl, s.client)
+	return s
 }
 
 func (s *ExactMultiversXScheme) Scheme() string {
@@ -41,147 +65,429 @@ func (s *ExactMultiversXScheme) CaipFamily() string {
 }
 
 func (s *ExactMultiversXScheme) GetExtra(network x402.Network) map[string]interface{} {
-	return nil
+	// Advertise the relayer so servers can forward it to clients in the requirements
+	if s.relayer == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		multiversx.ExtraKeyRelayer: s.relayer.Address(),
+	}
 }
 
 func (s *ExactMultiversXScheme) GetSigners(network x402.Network) []string {
-	// In the exact scheme, the Client is the main signer,
-	// but strictly GetSigners returns the addresses the *Facilitator* controls
-	// if it were acting as a wallet. However, standard implies "Signers" relevant to the payment?
-	// Actually, for "Exact", the facilitator relays.
-	// The prompt said: "Facilitator GetSigners is not implemented (should return at least main user)"
-	// This likely refers to the Go SDK's `GetSigners` usually returning the loaded wallet address.
-	// Since this is a "Facilitator" (Server) implementation, it might have a relayer wallet.
+	// In the exact scheme the client signs the transfer; the only key the
+	// facilitator controls is the relayer wallet paying gas for Relayed V3.
+	if s.relayer == nil {
+		return []string{}
+	}
+	return []string{s.relayer.Address()}
+}
 
-	// Assumption: We might need a Relayer Address here if we implement Relaying V1.
-	// For now, we return empty or a placeholder if we haven't loaded a PEM.
-	// TODO: Load Relayer Wallet.
-	return []string{"facilitator-address-placeholder"}
+// Close releases the relayer wallet, zeroing its secret key when supported
+func (s *ExactMultiversXScheme) Close() error {
+	if closer, ok := s.relayer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
 }
 
+// Verify checks the payment against the requirements. A bad payment yields IsValid=false with
+// one of the multiversx.InvalidReason* codes; Go errors are reserved for infrastructure failures
+// (gateway unreachable, relayer unable to sign).
 func (s *ExactMultiversXScheme) Verify(ctx context.Context, payload types.PaymentPayload, requirements types.PaymentRequirements) (*x402.VerifyResponse, error) {
 	// 1. Unmarshal directly to ExactRelayedPayload
-	payloadBytes, err := json.Marshal(payload.Payload)
+	relayedPayload, err := decodeRelayedPayload(payload)
 	if err != nil {
+		return &x402.VerifyResponse{
+			IsValid:       false,
+			InvalidReason: multiversx.InvalidReasonInvalidPayload,
+		}, nil
+	}
+	payer := relayedPayload.Data.Sender
+
+	if err := s.verifyPayload(ctx, relayedPayload, requirements); err != nil {
+		if verr, ok := multiversx.AsVerificationError(err); ok {
+			return &x402.VerifyResponse{
+				IsValid:       false,
+				InvalidReason: verr.Reason,
+				Payer:         payer,
+			}, nil
+		}
 		return nil, err
 	}
 
-	var relayedPayload multiversx.ExactRelayedPayload
-	if err := json.Unmarshal(payloadBytes, &relayedPayload); err != nil {
-		return nil, fmt.Errorf("invalid payload format: %v", err)
+	return &x402.VerifyResponse{
+		IsValid: true,
+		Payer:   payer,
+	}, nil
+}
+
+// verifyPayload runs every check of Verify on a decoded payload.
+// Rejections are *multiversx.VerificationError, anything else is an infrastructure failure.
+func (s *ExactMultiversXScheme) verifyPayload(ctx context.Context, relayedPayload multiversx.ExactRelayedPayload, requirements types.PaymentRequirements) error {
+	// 1. Well-formed transaction: addresses, value and chain ID, checked offline
+	if err := verifyStructure(relayedPayload.Data, addressHRP(requirements.PayTo)); err != nil {
+		return err
 	}
 
-	// 2. Perform Verification using Universal logic
-	isValid, err := multiversx.VerifyPayment(ctx, relayedPayload, requirements, s.verifyViaSimulation)
+	// 2. Requirements still valid: the signed expiry has not passed
+	if binding, err := multiversx.RequiredBinding(requirements); err == nil && binding.Expired(s.now()) {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonPaymentExpired, "payment expired at %d", binding.ValidUntil)
+	}
+
+	// 3. Signed for the chain of the requirements (and of our gateway)
+	if err := s.checkChain(relayedPayload.Data, requirements); err != nil {
+		return err
+	}
+
+	// 4. Relayed V3: only our own relayer may pay the gas
+	if err := s.checkRelayer(relayedPayload); err != nil {
+		return err
+	}
+
+	// 5. Gas our relayer pays must stay within the gas policy
+	if _, err := s.checkGasPolicy(ctx, relayedPayload); err != nil {
+		return err
+	}
+
+	// 6. Validate Requirements (Specific Fields), with every asset named by its identifier on this chain
+	requirements, err := s.tokens.ResolveAssets(relayedPayload.Data.ChainID, requirements)
+	if err != nil {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidRequirements, "%v", err)
+	}
+	if err := verifyRequirements(relayedPayload.Data, requirements); err != nil {
+		return err
+	}
+
+	// 7. Perform Verification using Universal logic (local signature, then simulation)
+	var simulator func(multiversx.ExactRelayedPayload) (string, error)
+	if s.simulate {
+		simulator = func(p multiversx.ExactRelayedPayload) (string, error) {
+			return s.verifyViaSimulation(ctx, p)
+		}
+	}
+	isValid, err := multiversx.VerifyPayment(ctx, relayedPayload, requirements, simulator)
 	if err != nil {
-		return nil, err // Returns invalid reason wrapped
+		return err
 	}
 	if !isValid {
-		return nil, fmt.Errorf("verification failed")
+		return multiversx.NewVerificationError(multiversx.InvalidReasonSimulationFailed, "verification failed")
+	}
+
+	// 8. Claim the payment: the same payload must not pay for another resource,
+	// nor pay again once its ledger entry was pruned
+	if err := s.checkAccountNonce(ctx, relayedPayload.Data); err != nil {
+		return err
+	}
+	return s.recordPayment(relayedPayload.Data, requirements)
+}
+
+// addressHRP returns the HRP of the chain's addresses, read from PayTo (custom chains
+// may use another HRP than "erd"). An invalid PayTo is rejected later on.
+func addressHRP(payTo string) string {
+	addr, err := multiversx.AddressFromBech32(payTo)
+	if err != nil {
+		return multiversx.DefaultHRP
 	}
+	return addr.HRP()
+}
 
-	// 3. Validate Requirements (Specific Fields)
-	expectedReceiver := requirements.PayTo
-	expectedAmount := requirements.Amount
-	if expectedAmount == "" {
-		return nil, errors.New("requirement amount is empty")
+// verifyStructure rejects transactions the node would refuse or that cannot be attributed
+// to a payer, before any gateway call is made. Addresses must use the chain's hrp.
+func verifyStructure(txData multiversx.Transaction, hrp string) error {
+	if !multiversx.IsValidAddressWithHRP(txData.Sender, hrp) {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidPayload, "invalid sender address %q", txData.Sender)
+	}
+	if !multiversx.IsValidAddressWithHRP(txData.Receiver, hrp) {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidPayload, "invalid receiver address %q", txData.Receiver)
+	}
+	if !isDecimal(txData.Value) {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidPayload, "invalid value %q", txData.Value)
 	}
+	if txData.ChainID == "" {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidPayload, "chain ID is empty")
+	}
+	return nil
+}
 
-	reqAsset := requirements.Asset
-	if reqAsset == "" {
-		reqAsset = "EGLD"
+// isDecimal accepts the plain base-10 digits the node expects in the value field
+func isDecimal(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
 	}
+	return true
+}
 
-	txData := relayedPayload.Data
+// verifyRequirements checks the transfer pays every required leg (requirements.Asset/Amount plus
+// Extra["assets"]) to requirements.PayTo
+func verifyRequirements(txData multiversx.Transaction, requirements types.PaymentRequirements) error {
+	expectedReceiver, err := multiversx.AddressFromBech32(requirements.PayTo)
+	if err != nil {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidRequirements, "invalid payTo: %v", err)
+	}
+	legs, err := multiversx.RequiredTransfers(requirements)
+	if err != nil {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidRequirements, "%v", err)
+	}
+	binding, err := multiversx.RequiredBinding(requirements)
+	if err != nil {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidRequirements, "%v", err)
+	}
 
-	if reqAsset == "EGLD" {
+	if legs[0].Identifier == "EGLD" {
 		// Case A: Direct EGLD
-		if txData.Receiver != expectedReceiver {
-			return nil, fmt.Errorf("receiver mismatch: expected %s, got %s", expectedReceiver, txData.Receiver)
-		}
-		if !multiversx.CheckBigInt(txData.Value, expectedAmount) {
-			return nil, fmt.Errorf("amount mismatch: expected %s, got %s", expectedAmount, txData.Value)
+		receiver, err := multiversx.AddressFromBech32(txData.Receiver)
+		if err != nil || !receiver.Equal(expectedReceiver) {
+			return multiversx.NewVerificationError(multiversx.InvalidReasonReceiverMismatch, "expected %s, got %s", expectedReceiver, txData.Receiver)
 		}
-	} else {
-		// Case B: ESDT Transfer
-		parts := strings.Split(txData.Data, "@")
-		if len(parts) < 6 || parts[0] != "MultiESDTNFTTransfer" {
-			return nil, errors.New("invalid ESDT transfer data format")
+		if !multiversx.CheckBigInt(txData.Value, legs[0].Amount.String()) {
+			return multiversx.NewVerificationError(multiversx.InvalidReasonInsufficientAmount, "expected %s, got %s", legs[0].Amount, txData.Value)
 		}
+		return verifyBinding(txData, binding)
+	}
 
-		// Decode Receiver (parts[1]) - Hex (Destination)
-		destHex := parts[1]
-		if !multiversx.IsValidHex(destHex) {
-			return nil, fmt.Errorf("invalid receiver hex")
-		}
+	// Case B: ESDT Transfer (fungible tokens, NFT/SFT nonces, or a bundle of them)
+	transfer, err := multiversx.DecodeTransferData(txData.Data, expectedReceiver.HRP())
+	if err != nil {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidPayload, "invalid ESDT transfer data: %v", err)
+	}
+	if err := verifyBinding(txData, binding); err != nil {
+		return err
+	}
 
-		// STRICT VERIFICATION: Ensure destHex matches expectedReceiver (PayTo)
-		// expectedReceiver is Bech32 (erd1...). We must decode it to get the pubkey hex.
-		_, pubKeyBytes, err := multiversx.DecodeBech32(expectedReceiver)
-		if err != nil {
-			return nil, fmt.Errorf("invalid expected receiver format (not bech32): %v", err)
-		}
-		expectedHex := hex.EncodeToString(pubKeyBytes)
+	// Token transfers move no EGLD: a value here would be paid on top, unchecked
+	if value, _ := new(big.Int).SetString(txData.Value, 10); value == nil || value.Sign() != 0 {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidPayload, "ESDT transfer must have value 0, got %s", txData.Value)
+	}
 
-		if destHex != expectedHex {
-			return nil, fmt.Errorf("receiver mismatch: encoded destination %s does not match requirement %s (%s)", destHex, expectedReceiver, expectedHex)
+	// STRICT VERIFICATION: the destination must be the PayTo public key
+	var dest multiversx.Address
+	switch transfer.Function {
+	case multiversx.FunctionMultiESDTNFTTransfer, multiversx.FunctionESDTNFTTransfer:
+		// Self-transfer naming the destination in the data: the built-in function runs on the sender
+		if !multiversx.AddressesEqual(txData.Receiver, txData.Sender) {
+			return multiversx.NewVerificationError(multiversx.InvalidReasonReceiverMismatch, "%s must be a self-transfer, receiver is %s", transfer.Function, txData.Receiver)
 		}
-
-		// Token Hex
-		tokenBytes, err := hex.DecodeString(parts[3])
+		dest = transfer.Receiver
+	case multiversx.FunctionESDTTransfer:
+		// Sent straight to the destination
+		dest, err = multiversx.AddressFromBech32(txData.Receiver)
 		if err != nil {
-			return nil, fmt.Errorf("invalid token hex")
-		}
-		if string(tokenBytes) != reqAsset {
-			return nil, fmt.Errorf("asset mismatch: expected %s, got %s", reqAsset, string(tokenBytes))
+			return multiversx.NewVerificationError(multiversx.InvalidReasonReceiverMismatch, "invalid receiver %s", txData.Receiver)
 		}
+	default:
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInvalidPayload, "unsupported transfer function %s", transfer.Function)
+	}
+	if !dest.Equal(expectedReceiver) {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonReceiverMismatch, "destination %s does not match requirement %s", dest, expectedReceiver)
+	}
 
-		// Amount Hex
-		amountBytes, err := hex.DecodeString(parts[5])
-		if err != nil {
-			return nil, fmt.Errorf("invalid amount hex")
-		}
-		amountBig := new(big.Int).SetBytes(amountBytes)
-		expectedBig, _ := new(big.Int).SetString(expectedAmount, 10)
-		if amountBig.Cmp(expectedBig) < 0 {
-			return nil, fmt.Errorf("amount too low")
+	// Each required leg is checked on its own; legs nobody asked for are extra value for the payee
+	for _, leg := range legs {
+		if err := verifyLeg(transfer.Transfers, leg); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	// fmt.Printf("Verification Successful. Hash: %s\n", simHash)
+// verifyBinding checks the signed data carries the resourceId (and expiry) of the requirements
+func verifyBinding(txData multiversx.Transaction, binding multiversx.PaymentBinding) error {
+	if binding.IsEmpty() || binding.Matches(txData) {
+		return nil
+	}
+	return multiversx.NewVerificationError(multiversx.InvalidReasonResourceMismatch, "payment is not bound to resourceId %q (validUntil %d)", binding.ResourceID, binding.ValidUntil)
+}
 
-	return &x402.VerifyResponse{
-		IsValid: true,
-	}, nil
+// verifyLeg sums the transfers of the required token (same identifier and nonce) and checks the
+// total: at least the amount for fungible tokens, exactly the quantity for NFTs/SFTs
+func verifyLeg(transfers []multiversx.TokenTransfer, leg multiversx.TokenTransfer) error {
+	token := multiversx.TokenIdentifier{Collection: leg.Identifier, Nonce: leg.Nonce}
+	paid := new(big.Int)
+	found := false
+	for _, t := range transfers {
+		if t.Identifier == leg.Identifier && t.Nonce == leg.Nonce {
+			paid.Add(paid, t.Amount)
+			found = true
+		}
+	}
+	if !found {
+		got := multiversx.TokenIdentifier{Collection: transfers[0].Identifier, Nonce: transfers[0].Nonce}
+		return multiversx.NewVerificationError(multiversx.InvalidReasonAssetMismatch, "expected %s, got %s", token, got)
+	}
+	if token.IsNonFungible() {
+		// NFT/SFT prices are a quantity of units, not a minimum
+		if paid.Cmp(leg.Amount) != 0 {
+			return multiversx.NewVerificationError(multiversx.InvalidReasonQuantityMismatch, "expected %s of %s, got %s", leg.Amount, token, paid)
+		}
+		return nil
+	}
+	if paid.Cmp(leg.Amount) < 0 {
+		return multiversx.NewVerificationError(multiversx.InvalidReasonInsufficientAmount, "expected %s of %s, got %s", leg.Amount, token, paid)
+	}
+	return nil
 }
 
 func (s *ExactMultiversXScheme) Settle(ctx context.Context, payload types.PaymentPayload, requirements types.PaymentRequirements) (*x402.SettleResponse, error) {
-	// TODO: Implement actual Relaying.
+	network := x402.Network(requirements.Network)
+
 	// 1. Recover ExactRelayedPayload
-	// 2. Broadcast to MultiversX API: POST /transaction/send
-	// 3. Return Hash
+	relayedPayload, err := decodeRelayedPayload(payload)
+	if err != nil {
+		return &x402.SettleResponse{
+			Success:     false,
+			ErrorReason: multiversx.FormatReason(multiversx.SettleReasonInvalidPayload, err.Error()),
+			Network:     network,
+		}, nil
+	}
+	payer := relayedPayload.Data.Sender
+
+	// 2. Re-run verification: never broadcast something Verify would reject
+	if err := s.verifyPayload(ctx, relayedPayload, requirements); err != nil {
+		if verr, ok := multiversx.AsVerificationError(err); ok {
+			code := multiversx.SettleReasonVerificationFailed
+			if verr.Reason == multiversx.InvalidReasonPaymentExpired {
+				code = multiversx.SettleReasonPaymentExpired
+			}
+			return &x402.SettleResponse{
+				Success:     false,
+				ErrorReason: multiversx.FormatReason(code, err.Error()),
+				Payer:       payer,
+				Network:     network,
+			}, nil
+		}
+		return nil, err
+	}
+
+	// 3. Relayed V3: co-sign as relayer
+	relayedPayload, err = s.cosign(ctx, relayedPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Book the relayer fee against the gas policy window
+	release, err := s.reserveRelayerFee(ctx, relayedPayload)
+	if err != nil {
+		if _, ok := multiversx.AsVerificationError(err); !ok {
+			return nil, err
+		}
+		return &x402.SettleResponse{
+			Success:     false,
+			ErrorReason: multiversx.FormatReason(multiversx.SettleReasonVerificationFailed, err.Error()),
+			Payer:       payer,
+			Network:     network,
+		}, nil
+	}
+
+	// 5. Broadcast to MultiversX API: POST /transaction/send
+	txHash, err := s.sendTransaction(ctx, relayedPayload)
+	if err != nil {
+		var gwErr *multiversx.GatewayError
+		if errors.As(err, &gwErr) {
+			release() // rejected by the gateway: never reached the chain
+			return &x402.SettleResponse{
+				Success:     false,
+				ErrorReason: multiversx.FormatReason(multiversx.ClassifySendError(gwErr.Message), gwErr.Message),
+				Payer:       payer,
+				Network:     network,
+			}, nil
+		}
+		return nil, err
+	}
+	// Broadcast: whatever the outcome on chain, the nonce is spent. The transaction is out,
+	// so a ledger failure must not hide its hash from the caller (who might pay again)
+	if err := s.markSettled(relayedPayload.Data, txHash); err != nil {
+		log.Printf("multiversx: settled %s but failed to update the payment ledger: %v", txHash, err)
+	}
+
+	// 6. Optionally wait until the transfer actually executed
+	if s.settlementMode == SettlementModeFinality {
+		return s.settleWithFinality(ctx, txHash, payer, requirements)
+	}
 
-	// Stub for now, as we need the Proxy/API client implementation details.
+	// 7. Return Hash
 	return &x402.SettleResponse{
 		Success:     true,
-		Transaction: "mock_settlement_hash",
+		Transaction: txHash,
+		Payer:       payer,
+		Network:     network,
 	}, nil
 }
 
-func (s *ExactMultiversXScheme) verifyViaSimulation(payload multiversx.ExactRelayedPayload) (string, error) {
-	reqBody := multiversx.SimulationRequest{
-		Nonce:     payload.Data.Nonce,
-		Value:     payload.Data.Value,
-		Receiver:  payload.Data.Receiver,
-		Sender:    payload.Data.Sender,
-		GasPrice:  payload.Data.GasPrice,
-		GasLimit:  payload.Data.GasLimit,
-		Data:      base64.StdEncoding.EncodeToString([]byte(payload.Data.Data)),
-		ChainID:   payload.Data.ChainID,
-		Version:   payload.Data.Version,
-		Signature: payload.Data.Signature,
+// decodeRelayedPayload converts the generic x402 payload map into ExactRelayedPayload
+func decodeRelayedPayload(payload types.PaymentPayload) (multiversx.ExactRelayedPayload, error) {
+	var relayedPayload multiversx.ExactRelayedPayload
+
+	payloadBytes, err := json.Marshal(payload.Payload)
+	if err != nil {
+		return relayedPayload, err
+	}
+
+	if err := json.Unmarshal(payloadBytes, &relayedPayload); err != nil {
+		return relayedPayload, fmt.Errorf("invalid payload format: %v", err)
+	}
+
+	return relayedPayload, nil
+}
+
+// sendTransaction broadcasts the signed transaction and returns its hash.
+// Rejections reported by the gateway are returned as *multiversx.GatewayError.
+func (s *ExactMultiversXScheme) sendTransaction(ctx context.Context, payload multiversx.ExactRelayedPayload) (string, error) {
+	jsonBody, err := json.Marshal(payload.Data.GatewayRequest())
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal send request: %v", err)
+	}
+
+	url := fmt.Sprintf("%s/transaction/send", s.config.APIUrl)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to build send request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var sendResp multiversx.SendTransactionResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&sendResp)
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return "", fmt.Errorf("send API returned status %d: %s", resp.StatusCode, sendResp.Error)
+	}
+	if decodeErr != nil {
+		return "", fmt.Errorf("failed to decode send response: %v", decodeErr)
+	}
+	if resp.StatusCode != http.StatusOK || sendResp.Error != "" {
+		return "", &multiversx.GatewayError{
+			StatusCode: resp.StatusCode,
+			Message:    sendResp.Error,
+			Code:       sendResp.Code,
+		}
+	}
+	if sendResp.Data.TxHash == "" {
+		return "", errors.New("send API returned empty transaction hash")
+	}
+
+	return sendResp.Data.TxHash, nil
+}
+
+func (s *ExactMultiversXScheme) verifyViaSimulation(ctx context.Context, payload multiversx.ExactRelayedPayload) (string, error) {
+	// The node checks the relayer signature as well, so simulate the co-signed transaction
+	payload, err := s.cosign(ctx, payload)
+	if err != nil {
+		return "", err
 	}
 
+	reqBody := payload.Data.GatewayRequest()
+
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal simulation request: %v", err)
@@ -194,24 +500,43 @@ func (s *ExactMultiversXScheme) verifyViaSimulation(payload multiversx.ExactRela
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		var bodyErr map[string]interface{}
-		json.NewDecoder(resp.Body).Decode(&bodyErr)
-		return "", fmt.Errorf("simulation API returned non-200 status: %d Body: %v", resp.StatusCode, bodyErr)
+	// 5xx: the gateway is in trouble, not the payment
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return "", fmt.Errorf("simulation API returned status %d", resp.StatusCode)
 	}
 
 	var simResp multiversx.SimulationResponse
 	if err := json.NewDecoder(resp.Body).Decode(&simResp); err != nil {
-		return "", fmt.Errorf("failed to decode simulation response: %v", err)
+		return "", fmt.Errorf("failed to decode simulation response (status %d): %v", resp.StatusCode, err)
 	}
 
-	if simResp.Error != "" {
-		return "", fmt.Errorf("simulation returned error: %s (code: %s)", simResp.Error, simResp.Code)
+	if simResp.Error != "" || resp.StatusCode != http.StatusOK {
+		return "", multiversx.NewVerificationError(classifySimulationError(simResp.Error), "%s (status %d, code: %s)", simResp.Error, resp.StatusCode, simResp.Code)
 	}
 
 	if simResp.Data.Result.Status != "success" {
-		return "", fmt.Errorf("simulation status not success: %s", simResp.Data.Result.Status)
+		return "", multiversx.NewVerificationError(multiversx.InvalidReasonSimulationFailed, "simulation status not success: %s", simResp.Data.Result.Status)
+	}
+
+	// Sanity check: the node must have simulated exactly the transaction we hold
+	if simResp.Data.Result.Hash != "" {
+		localHash, err := payload.Data.Hash()
+		if err != nil {
+			return "", multiversx.NewVerificationError(multiversx.InvalidReasonInvalidPayload, "failed to compute transaction hash: %v", err)
+		}
+		if !strings.EqualFold(localHash, simResp.Data.Result.Hash) {
+			return "", multiversx.NewVerificationError(multiversx.InvalidReasonSimulationFailed, "simulation hash mismatch: expected %s, got %s", localHash, simResp.Data.Result.Hash)
+		}
 	}
 
 	return simResp.Data.Result.Hash, nil
 }
+
+// classifySimulationError maps a simulation rejection to an InvalidReason code
+func classifySimulationError(message string) string {
+	switch multiversx.ClassifySendError(message) {
+	case multiversx.SettleReasonNonceTooLow, multiversx.SettleReasonNonceTooHigh:
+		return multiversx.InvalidReasonNonceStale
+	}
+	return multiversx.InvalidReasonSimulationFailed
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"x402-integration/mechanisms/multiversx"
+)
+
+// Option configures the server scheme
+type Option func(*ExactMultiversXScheme)
+
+// WithNetworkProvider sets the source of the gas schedule used for the requirement gas hints.
+// Without it the protocol defaults are used.
+func WithNetworkProvider(provider multiversx.NetworkProvider) Option {
+	return func(s *ExactMultiversXScheme) {
+		s.provider = provider
+	}
+}
+
+// WithTokenRegistry sets the tokens prices and requirements may name, replacing the default
+// registry (EGLD and the well-known stablecoins). The registry may be shared: WithTokenDecimals
+// tokens are added to this scheme's own copy, whatever the order of the options.
+func WithTokenRegistry(tokens *multiversx.TokenRegistry) Option {
+	return func(s *ExactMultiversXScheme) {
+		if tokens != nil {
+			s.tokens = tokens
+		}
+	}
+}
+
+// WithTokenDecimals registers the decimals of a token so prices can be given in token units
+// ("2.5 MYTOKEN-abcdef") without fetching them. EGLD and the well-known stablecoins are known by default.
+// An invalid token is reported by Err, ParsePrice and EnhancePaymentRequirements.
+func WithTokenDecimals(asset string, decimals int) Option {
+	return func(s *ExactMultiversXScheme) {
+		s.tokenOverrides = append(s.tokenOverrides, multiversx.Token{Name: asset, Decimals: decimals, Identifiers: map[string]string{multiversx.AnyChain: asset}})
+	}
+}
+
+// WithDollarAsset sets the token "$" prices are paid in ("USDC" by default): a name
+// known to the registry or an identifier. Its decimals must be known or fetchable.
+func WithDollarAsset(asset string) Option {
+	return func(s *ExactMultiversXScheme) {
+		s.dollarAsset = asset
+	}
+}
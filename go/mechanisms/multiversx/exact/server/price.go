@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"x402-integration/mechanisms/multiversx"
+
+	x402 "github.com/coinbase/x402/go"
+)
+
+// defaultDollarAsset is the token "$" prices are paid in, resolved per network
+const defaultDollarAsset = "USDC"
+
+// parseAmount converts a human-readable amount of the asset to atomic units
+func (s *ExactMultiversXScheme) parseAmount(amount string, asset string, chainID string) (x402.AssetAmount, error) {
+	token, err := s.tokens.Resolve(context.Background(), chainID, asset)
+	if err != nil {
+		return x402.AssetAmount{}, fmt.Errorf("invalid price: %v", err)
+	}
+	units, err := multiversx.ParseUnits(amount, token.Decimals)
+	if err != nil {
+		return x402.AssetAmount{}, fmt.Errorf("invalid price for %s: %v", token.Identifier, err)
+	}
+	return x402.AssetAmount{Asset: token.Identifier, Amount: units.String()}, nil
+}
+
+// parsePriceString reads "0.05 EGLD", "1.5 USDC-c76f1f", "$0.01" or a bare "0.05" (EGLD)
+func (s *ExactMultiversXScheme) parsePriceString(price string, chainID string) (x402.AssetAmount, error) {
+	price = strings.TrimSpace(price)
+	if dollars, ok := strings.CutPrefix(price, "$"); ok {
+		return s.parseAmount(dollars, s.dollarAsset, chainID)
+	}
+
+	fields := strings.Fields(price)
+	switch len(fields) {
+	case 1:
+		return s.parseAmount(fields[0], "EGLD", chainID)
+	case 2:
+		return s.parseAmount(fields[0], fields[1], chainID)
+	default:
+		return x402.AssetAmount{}, fmt.Errorf("invalid price %q: expected \"<amount> <asset>\"", price)
+	}
+}
+
+// formatNumber renders a JSON/Go number as the shortest decimal that reads back the same,
+// so 0.25 stays "0.25" instead of picking up binary rounding noise
+func formatNumber(n interface{}) (string, bool) {
+	switch v := n.(type) {
+	case float64:
+		return formatFloat(v, 64)
+	case float32:
+		return formatFloat(float64(v), 32)
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	default:
+		return "", false
+	}
+}
+
+func formatFloat(f float64, bitSize int) (string, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", false
+	}
+	return strconv.FormatFloat(f, 'f', -1, bitSize), true
+}
+
+// checkAtomic rejects atomic amounts that are not positive integers
+func checkAtomic(amount string) error {
+	units, err := multiversx.CheckAmount(amount)
+	if err != nil {
+		return fmt.Errorf("invalid price: %v", err)
+	}
+	if units.Sign() <= 0 {
+		return fmt.Errorf("invalid price: amount must be positive, got %s", amount)
+	}
+	return nil
+}
@@ -2,7 +2,9 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"x402-integration/mechanisms/multiversx"
 
@@ -12,55 +14,124 @@ import (
 
 // ExactMultiversXScheme implements SchemeNetworkServer for MultiversX
 type ExactMultiversXScheme struct {
-	// Config if needed
+	provider       multiversx.NetworkProvider // optional: gas schedule for requirement hints
+	tokens         *multiversx.TokenRegistry  // asset names, identifiers and decimals
+	tokenOverrides []multiversx.Token         // WithTokenDecimals, registered on a copy of tokens
+	dollarAsset    string                     // token "$" prices are paid in
+	configErr      error                      // invalid options, see Err
+	now            func() time.Time
 }
 
-func NewExactMultiversXScheme() *ExactMultiversXScheme {
-	return &ExactMultiversXScheme{}
+func NewExactMultiversXScheme(opts ...Option) *ExactMultiversXScheme {
+	s := &ExactMultiversXScheme{
+		tokens:      multiversx.NewDefaultTokenRegistry(),
+		dollarAsset: defaultDollarAsset,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.applyTokenOverrides()
+	return s
+}
+
+// applyTokenOverrides registers the WithTokenDecimals tokens on a copy of the registry,
+// leaving a registry shared with other schemes untouched
+func (s *ExactMultiversXScheme) applyTokenOverrides() {
+	if len(s.tokenOverrides) == 0 {
+		return
+	}
+	s.tokens = s.tokens.Clone()
+	for _, token := range s.tokenOverrides {
+		if err := s.tokens.Register(token); err != nil {
+			s.configErr = errors.Join(s.configErr, fmt.Errorf("WithTokenDecimals(%q, %d): %v", token.Name, token.Decimals, err))
+		}
+	}
+}
+
+// Err reports options that could not be applied (e.g. an invalid WithTokenDecimals).
+// ParsePrice and EnhancePaymentRequirements fail with it too.
+func (s *ExactMultiversXScheme) Err() error {
+	return s.configErr
 }
 
 func (s *ExactMultiversXScheme) Scheme() string {
 	return multiversx.SchemeExact
 }
 
+// ParsePrice converts a route price to atomic units. It accepts an x402.AssetAmount or a map
+// with a string "amount" (already atomic), a number (0.05, in EGLD), a string such as
+// "0.05 EGLD", "1.5 USDC" or "$0.01", or a map with a numeric "amount" in token units.
+// Asset names resolve to their identifier on the network ("USDC" is USDC-c76f1f on mainnet);
+// an empty network defaults to devnet, as multiversx.ChainIDFromNetwork does.
 func (s *ExactMultiversXScheme) ParsePrice(price x402.Price, network x402.Network) (x402.AssetAmount, error) {
-	// Price is interface{}, usually map[string]interface{} from JSON
-	// We expect "amount" and "asset" keys.
-
-	// Try casting to AssetAmount struct first as it's the expected type
-	pStruct, ok := price.(x402.AssetAmount)
-	if ok {
-		// Just validate/defaults
-		if pStruct.Asset == "" {
-			pStruct.Asset = "EGLD"
-		}
-		return pStruct, nil
+	if s.configErr != nil {
+		return x402.AssetAmount{}, s.configErr
 	}
+	chainID, err := multiversx.ChainIDFromNetwork(string(network))
+	if err != nil {
+		return x402.AssetAmount{}, err
+	}
+
+	switch p := price.(type) {
+	case x402.AssetAmount:
+		if p.Asset == "" {
+			p.Asset = "EGLD"
+		}
+		if p.Asset, err = s.tokens.Identifier(chainID, p.Asset); err != nil {
+			return x402.AssetAmount{}, fmt.Errorf("invalid price: %v", err)
+		}
+		if err := checkAtomic(p.Amount); err != nil {
+			return x402.AssetAmount{}, err
+		}
+		return p, nil
 
-	// If not a struct, try map (e.g. from JSON interface{})
-	if pMap, okMap := price.(map[string]interface{}); okMap {
-		amount, _ := pMap["amount"].(string)
-		asset, _ := pMap["asset"].(string)
+	case string:
+		return s.parsePriceString(p, chainID)
 
+	case map[string]interface{}:
+		asset, _ := p["asset"].(string)
 		if asset == "" {
 			asset = "EGLD"
 		}
-
-		return x402.AssetAmount{
-			Asset:  asset,
-			Amount: amount,
-		}, nil
+		// A string amount is atomic, as before; a number is in token units
+		if amount, ok := p["amount"].(string); ok {
+			identifier, err := s.tokens.Identifier(chainID, asset)
+			if err != nil {
+				return x402.AssetAmount{}, fmt.Errorf("invalid price: %v", err)
+			}
+			if err := checkAtomic(amount); err != nil {
+				return x402.AssetAmount{}, err
+			}
+			return x402.AssetAmount{Asset: identifier, Amount: amount}, nil
+		}
+		if amount, ok := formatNumber(p["amount"]); ok {
+			return s.parseAmount(amount, asset, chainID)
+		}
+		return x402.AssetAmount{}, fmt.Errorf("invalid price: amount must be a string or a number, got %T", p["amount"])
 	}
 
-	return x402.AssetAmount{}, fmt.Errorf("invalid price format: expected AssetAmount struct or map")
+	if amount, ok := formatNumber(price); ok {
+		return s.parseAmount(amount, "EGLD", chainID)
+	}
+	return x402.AssetAmount{}, fmt.Errorf("invalid price format: expected AssetAmount struct, map, string or number, got %T", price)
 }
 
+// EnhancePaymentRequirements resolves asset names, forwards the facilitator's relayer and adds
+// the payment binding and gas hints. Unless the merchant set them in Extra, each call draws a new
+// random resourceId and stamps validUntil from the current time, so the client signs a payment
+// for this one issuance: verify it against these requirements, stored until the paid request
+// comes back (e.g. keyed by resourceId), not against requirements rebuilt for that request.
+// Servers that rebuild requirements statelessly set Extra["resourceId"] (and "validUntil") themselves.
 func (s *ExactMultiversXScheme) EnhancePaymentRequirements(
 	ctx context.Context,
 	requirements types.PaymentRequirements,
 	supportedKind types.SupportedKind,
 	extensions []string,
 ) (types.PaymentRequirements, error) {
+	if s.configErr != nil {
+		return requirements, s.configErr
+	}
 	// Create a copy to avoid side effects on the passed map
 	reqCopy := requirements
 	if reqCopy.Extra != nil {
@@ -73,15 +144,71 @@ func (s *ExactMultiversXScheme) EnhancePaymentRequirements(
 		reqCopy.Extra = make(map[string]interface{})
 	}
 
-	// Default to EGLD if no asset
+	// Default to EGLD if no asset, and name every token by its identifier on this network
 	if reqCopy.Asset == "" {
 		reqCopy.Asset = "EGLD"
 	}
+	chainID, err := multiversx.ChainIDFromNetwork(string(reqCopy.Network))
+	if err != nil {
+		return reqCopy, err
+	}
+	if reqCopy, err = s.tokens.ResolveAssets(chainID, reqCopy); err != nil {
+		return reqCopy, err
+	}
+
+	// Forward the facilitator's relayer so clients can build Relayed V3 (gasless) payloads
+	if relayer, ok := supportedKind.Extra[multiversx.ExtraKeyRelayer].(string); ok && relayer != "" {
+		if _, exists := reqCopy.Extra[multiversx.ExtraKeyRelayer]; !exists {
+			reqCopy.Extra[multiversx.ExtraKeyRelayer] = relayer
+		}
+	}
 
 	// Ensure PayTo is present
 	if reqCopy.PayTo == "" {
 		return reqCopy, fmt.Errorf("PayTo is required for MultiversX payments")
 	}
 
+	// Bind the payment to this resource: an unguessable ID unless the merchant chose one
+	if _, exists := reqCopy.Extra[multiversx.ExtraKeyResourceID]; !exists {
+		resourceID, err := multiversx.NewResourceID()
+		if err != nil {
+			return reqCopy, err
+		}
+		reqCopy.Extra[multiversx.ExtraKeyResourceID] = resourceID
+	}
+	// Expiry: the client signs validUntil with the resourceId, the facilitator enforces it
+	if _, exists := reqCopy.Extra[multiversx.ExtraKeyValidUntil]; !exists && reqCopy.MaxTimeoutSeconds > 0 {
+		issuedAt := s.now().Unix()
+		reqCopy.Extra[multiversx.ExtraKeyIssuedAt] = issuedAt
+		reqCopy.Extra[multiversx.ExtraKeyValidUntil] = issuedAt + int64(reqCopy.MaxTimeoutSeconds)
+	}
+	if _, err := multiversx.RequiredBinding(reqCopy); err != nil {
+		return reqCopy, err
+	}
+
+	// Gas hints for wallets that do not estimate themselves, computed on the
+	// same transaction shape the client builds (PayTo stands in for the unknown sender)
+	if _, exists := reqCopy.Extra[multiversx.ExtraKeyGasLimit]; !exists {
+		tx, err := multiversx.NewPaymentTransaction(reqCopy, reqCopy.PayTo, multiversx.TransferFormatMultiESDT)
+		if err != nil {
+			return reqCopy, err
+		}
+		gasLimit, gasPrice := s.estimateGas(ctx, tx)
+		reqCopy.Extra[multiversx.ExtraKeyGasLimit] = gasLimit
+		reqCopy.Extra[multiversx.ExtraKeyGasPrice] = gasPrice
+	}
+
 	return reqCopy, nil
 }
+
+// estimateGas uses the configured provider's gas schedule, falling back to the protocol defaults
+// so a gateway outage does not prevent issuing payment requirements
+func (s *ExactMultiversXScheme) estimateGas(ctx context.Context, tx multiversx.Transaction) (uint64, uint64) {
+	if s.provider != nil {
+		if gasLimit, gasPrice, err := multiversx.NewGasEstimator(s.provider, false).Estimate(ctx, tx); err == nil {
+			return gasLimit, gasPrice
+		}
+	}
+	params := multiversx.DefaultNetworkParameters(tx.ChainID)
+	return multiversx.EstimateGasLimit(params, tx), params.MinGasPrice
+}
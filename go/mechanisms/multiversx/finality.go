@@ -0,0 +1,94 @@
+package multiversx
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// Transaction statuses reported by the gateway
+const (
+	TxStatusPending           = "pending"
+	TxStatusReceived          = "received"
+	TxStatusPartiallyExecuted = "partially-executed"
+	TxStatusExecuted          = "executed"
+	TxStatusSuccess           = "success"
+	TxStatusFail              = "fail"
+	TxStatusInvalid           = "invalid"
+)
+
+const signalErrorIdentifier = "signalError"
+
+// gasRefundMessage marks the refund result of a successful relayed transaction
+const gasRefundMessage = "gas refund for relayer"
+
+// IsFinalStatus reports whether a status returned by /transaction/{hash}/status
+// will not change anymore.
+func IsFinalStatus(status string) bool {
+	switch status {
+	case TxStatusSuccess, TxStatusExecuted, TxStatusFail, TxStatusInvalid:
+		return true
+	}
+	return false
+}
+
+// IsSuccessfulStatus reports whether a final status means the transfer executed
+func IsSuccessfulStatus(status string) bool {
+	return status == TxStatusSuccess || status == TxStatusExecuted
+}
+
+// IsCrossShard reports whether the transaction spans two shards
+func (tx TransactionOnNetwork) IsCrossShard() bool {
+	return tx.SourceShard != tx.DestinationShard
+}
+
+// IsExecutedAtDestination reports whether the destination shard has executed the transaction.
+// Intra-shard transactions are executed at destination as soon as they are final.
+func (tx TransactionOnNetwork) IsExecutedAtDestination() bool {
+	if !tx.IsCrossShard() {
+		return true
+	}
+	return tx.NotarizedAtDestinationInMetaNonce > 0
+}
+
+// FailureReason returns the most specific error found in the transaction outcome:
+// the first signalError log (on the transaction or its smart contract results),
+// then the first non-empty smart contract result return message other than a gas refund.
+// An empty string means no error was reported.
+func (tx TransactionOnNetwork) FailureReason() string {
+	if msg := signalErrorMessage(tx.Logs); msg != "" {
+		return msg
+	}
+	for _, scr := range tx.SmartContractResults {
+		if msg := signalErrorMessage(scr.Logs); msg != "" {
+			return msg
+		}
+	}
+	for _, scr := range tx.SmartContractResults {
+		if scr.ReturnMessage != "" && scr.ReturnMessage != gasRefundMessage {
+			return scr.ReturnMessage
+		}
+	}
+	return ""
+}
+
+func signalErrorMessage(logs *TransactionLogs) string {
+	if logs == nil {
+		return ""
+	}
+	for _, event := range logs.Events {
+		if event.Identifier != signalErrorIdentifier {
+			continue
+		}
+		// Topics: [address, message]
+		if len(event.Topics) > 1 {
+			if msg, err := base64.StdEncoding.DecodeString(event.Topics[1]); err == nil && len(msg) > 0 {
+				return string(msg)
+			}
+		}
+		if data, err := base64.StdEncoding.DecodeString(event.Data); err == nil && len(data) > 0 {
+			return strings.TrimPrefix(string(data), "@")
+		}
+		return signalErrorIdentifier
+	}
+	return ""
+}
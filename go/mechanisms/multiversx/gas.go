@@ -0,0 +1,117 @@
+package multiversx
+
+import (
+	"context"
+	"strings"
+)
+
+// Protocol defaults, used when /network/config is unavailable
+const (
+	DefaultMinGasLimit      = 50000
+	DefaultGasPerDataByte   = 1500
+	DefaultMinGasPrice      = 1000000000
+	DefaultGasPriceModifier = 0.01
+)
+
+// Execution costs of the built-in transfer functions, on top of the move-balance cost
+// (same schedule as the official SDKs' transfer factories)
+const (
+	ESDTTransferGas                 = 200000
+	ESDTTransferAdditionalGas       = 100000
+	ESDTNFTTransferGas              = 200000
+	MultiESDTNFTTransferGasPerToken = 200000
+	ESDTNFTTransferAdditionalGas    = 800000
+
+	// GuardianGasOverhead is the extra gas a guarded transaction consumes
+	GuardianGasOverhead = 50000
+)
+
+// Requirements Extra keys carrying the server's gas hints
+const (
+	ExtraKeyGasLimit = "gasLimit"
+	ExtraKeyGasPrice = "gasPrice"
+)
+
+// DefaultNetworkParameters returns the mainnet gas schedule for chainID
+func DefaultNetworkParameters(chainID string) NetworkParameters {
+	return NetworkParameters{
+		ChainID:               chainID,
+		MinGasLimit:           DefaultMinGasLimit,
+		GasPerDataByte:        DefaultGasPerDataByte,
+		MinGasPrice:           DefaultMinGasPrice,
+		GasPriceModifier:      DefaultGasPriceModifier,
+		MinTransactionVersion: 1,
+	}
+}
+
+// MoveBalanceGas is the cost of carrying the transaction and its data
+func (p NetworkParameters) MoveBalanceGas(dataLength int) uint64 {
+	return p.MinGasLimit + p.GasPerDataByte*uint64(dataLength)
+}
+
+// EstimateGasLimit computes the gas limit of tx from the network gas schedule:
+// move balance + data bytes + built-in function cost + relayer and guardian overheads.
+func EstimateGasLimit(params NetworkParameters, tx Transaction) uint64 {
+	gas := params.MoveBalanceGas(len(tx.Data)) + transferExecutionGas(tx.Data)
+	if tx.IsRelayed() {
+		gas += RelayerGasOverhead
+	}
+	if tx.IsGuarded() || tx.Guardian != "" {
+		gas += GuardianGasOverhead
+	}
+	return gas
+}
+
+// transferExecutionGas returns the execution cost of the built-in token transfer in data, if any
+func transferExecutionGas(data string) uint64 {
+	switch strings.SplitN(data, "@", 2)[0] {
+	case FunctionESDTTransfer:
+		return ESDTTransferGas + ESDTTransferAdditionalGas
+	case FunctionESDTNFTTransfer:
+		return ESDTNFTTransferGas + ESDTNFTTransferAdditionalGas
+	case FunctionMultiESDTNFTTransfer:
+		transfers := uint64(1)
+		if d, err := DecodeTransferData(data, ""); err == nil {
+			transfers = uint64(len(d.Transfers))
+		}
+		return MultiESDTNFTTransferGasPerToken*transfers + ESDTNFTTransferAdditionalGas
+	}
+	return 0
+}
+
+// TransactionCostEstimator is implemented by providers able to dry-run a transaction
+// (POST /transaction/cost) and return the gas it consumes
+type TransactionCostEstimator interface {
+	EstimateTransactionCost(ctx context.Context, tx Transaction) (uint64, error)
+}
+
+// GasEstimator fills gas limit and gas price from the network's gas schedule
+type GasEstimator struct {
+	provider NetworkProvider
+	refine   bool
+}
+
+// NewGasEstimator creates an estimator reading /network/config through provider.
+// With refine, providers implementing TransactionCostEstimator are asked for the
+// exact cost, falling back to the local estimate when the dry-run fails.
+func NewGasEstimator(provider NetworkProvider, refine bool) *GasEstimator {
+	return &GasEstimator{provider: provider, refine: refine}
+}
+
+// Estimate returns the gas limit and gas price for tx
+func (e *GasEstimator) Estimate(ctx context.Context, tx Transaction) (uint64, uint64, error) {
+	params, err := e.provider.GetNetworkConfig(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	gasLimit := EstimateGasLimit(params, tx)
+	if e.refine {
+		if estimator, ok := e.provider.(TransactionCostEstimator); ok {
+			if cost, err := estimator.EstimateTransactionCost(ctx, tx); err == nil && cost > 0 {
+				gasLimit = cost
+			}
+		}
+	}
+	return gasLimit, params.MinGasPrice, nil
+}
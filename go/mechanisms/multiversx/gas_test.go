@@ -0,0 +1,70 @@
+package multiversx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEstimateGasLimit(t *testing.T) {
+	params := DefaultNetworkParameters("D")
+	multiTransfer := "MultiESDTNFTTransfer@" + bobPubKeyHex + "@02@555344432d313233@00@64@5745474c442d313233@00@64"
+
+	tests := []struct {
+		name     string
+		tx       Transaction
+		expected uint64
+	}{
+		{"EGLD", Transaction{}, 50000},
+		{"EGLD with note", Transaction{Data: "inv_123"}, 50000 + 7*1500},
+		{"ESDTTransfer", Transaction{Data: "ESDTTransfer@555344432d313233@64"}, 50000 + 32*1500 + 300000},
+		{"MultiESDT x2", Transaction{Data: multiTransfer}, 50000 + uint64(len(multiTransfer))*1500 + 2*200000 + 800000},
+		{"Relayed", Transaction{Relayer: bobAddress}, 50000 + RelayerGasOverhead},
+		{"Guarded", Transaction{Guardian: bobAddress, Options: TransactionOptionGuarded}, 50000 + GuardianGasOverhead},
+	}
+
+	for _, tc := range tests {
+		if got := EstimateGasLimit(params, tc.tx); got != tc.expected {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestGasEstimator_Refinement(t *testing.T) {
+	var costBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/network/config":
+			w.Write([]byte(`{"data":{"config":{"erd_chain_id":"D","erd_min_gas_limit":50000,"erd_gas_per_data_byte":1500,"erd_min_gas_price":1000000000,"erd_gas_price_modifier":"0.01"}},"code":"successful"}`))
+		case "/transaction/cost":
+			raw, _ := io.ReadAll(r.Body)
+			costBody = string(raw)
+			w.Write([]byte(`{"data":{"txGasUnits":61234,"returnMessage":""},"code":"successful"}`))
+		}
+	}))
+	defer server.Close()
+
+	tx := Transaction{Sender: aliceAddress, Receiver: bobAddress, Value: "1", Data: "inv_123", ChainID: "D", Version: 1}
+
+	gasLimit, gasPrice, err := NewGasEstimator(NewGatewayProvider(server.URL, nil), false).Estimate(context.Background(), tx)
+	if err != nil {
+		t.Fatalf("Estimate: %v", err)
+	}
+	if gasLimit != 50000+7*1500 || gasPrice != 1000000000 {
+		t.Errorf("Unexpected local estimate: %d @ %d", gasLimit, gasPrice)
+	}
+
+	gasLimit, _, err = NewGasEstimator(NewGatewayProvider(server.URL, nil), true).Estimate(context.Background(), tx)
+	if err != nil {
+		t.Fatalf("Estimate: %v", err)
+	}
+	if gasLimit != 61234 {
+		t.Errorf("Expected refined gas 61234, got %d", gasLimit)
+	}
+	if !strings.Contains(costBody, `"data":"aW52XzEyMw=="`) {
+		t.Errorf("Expected base64 data in cost request, got %s", costBody)
+	}
+}
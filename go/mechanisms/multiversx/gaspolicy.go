@@ -0,0 +1,98 @@
+package multiversx
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Operation types a gas policy can cap separately
+const (
+	OperationMoveBalance          = "MoveBalance"
+	OperationESDTTransfer         = "ESDTTransfer"
+	OperationESDTNFTTransfer      = "ESDTNFTTransfer"
+	OperationMultiESDTNFTTransfer = "MultiESDTNFTTransfer"
+)
+
+// TransactionOperation classifies tx by its built-in function. Anything else is a move balance.
+func TransactionOperation(tx Transaction) string {
+	switch fn := strings.SplitN(tx.Data, "@", 2)[0]; fn {
+	case OperationESDTTransfer, OperationESDTNFTTransfer, OperationMultiESDTNFTTransfer:
+		return fn
+	}
+	return OperationMoveBalance
+}
+
+// ComputeFee returns the fee in atomic EGLD units paid for tx if it consumes its whole gas limit.
+// Move balance gas is charged at the full gas price, the remainder at gasPrice * gasPriceModifier.
+func ComputeFee(params NetworkParameters, tx Transaction) *big.Int {
+	moveGas := params.MoveBalanceGas(len(tx.Data))
+	if tx.IsRelayed() {
+		moveGas += RelayerGasOverhead
+	}
+	if tx.IsGuarded() {
+		moveGas += GuardianGasOverhead
+	}
+
+	gasPrice := new(big.Int).SetUint64(tx.GasPrice)
+	if tx.GasLimit <= moveGas {
+		return new(big.Int).Mul(new(big.Int).SetUint64(tx.GasLimit), gasPrice)
+	}
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(moveGas), gasPrice)
+	processingPrice := uint64(float64(tx.GasPrice) * params.GasPriceModifier)
+	processing := new(big.Int).Mul(new(big.Int).SetUint64(tx.GasLimit-moveGas), new(big.Int).SetUint64(processingPrice))
+	return fee.Add(fee, processing)
+}
+
+// GasPolicy bounds the gas a facilitator agrees to pay for as relayer. Zero fields are unlimited.
+type GasPolicy struct {
+	// MaxGasLimit caps the gas limit per operation type (see TransactionOperation)
+	MaxGasLimit map[string]uint64
+
+	// MaxGasPriceMultiple caps the gas price at this multiple of the network minimum
+	MaxGasPriceMultiple uint64
+
+	// MaxFeePerPayment caps the fee of a single transaction, in atomic EGLD units
+	MaxFeePerPayment *big.Int
+
+	// MaxFeePerWindow caps the total fee paid within Window, in atomic EGLD units
+	MaxFeePerWindow *big.Int
+	Window          time.Duration
+}
+
+// DefaultGasPolicy allows the gas the estimator produces for up to 4 token transfers
+// with a resourceId, at no more than twice the minimum gas price.
+func DefaultGasPolicy() GasPolicy {
+	return GasPolicy{
+		MaxGasLimit: map[string]uint64{
+			OperationMoveBalance:          500000,
+			OperationESDTTransfer:         1000000,
+			OperationESDTNFTTransfer:      1500000,
+			OperationMultiESDTNFTTransfer: 3000000,
+		},
+		MaxGasPriceMultiple: 2,
+	}
+}
+
+// Check validates tx against the static limits of the policy and returns its maximum fee
+func (p GasPolicy) Check(params NetworkParameters, tx Transaction) (*big.Int, error) {
+	op := TransactionOperation(tx)
+	if max, ok := p.MaxGasLimit[op]; ok && max > 0 && tx.GasLimit > max {
+		return nil, fmt.Errorf("gas limit %d exceeds maximum %d for %s", tx.GasLimit, max, op)
+	}
+
+	if p.MaxGasPriceMultiple > 0 && params.MinGasPrice > 0 {
+		maxPrice := new(big.Int).Mul(new(big.Int).SetUint64(params.MinGasPrice), new(big.Int).SetUint64(p.MaxGasPriceMultiple))
+		if new(big.Int).SetUint64(tx.GasPrice).Cmp(maxPrice) > 0 {
+			return nil, fmt.Errorf("gas price %d exceeds maximum %s (%dx network minimum)", tx.GasPrice, maxPrice, p.MaxGasPriceMultiple)
+		}
+	}
+
+	fee := ComputeFee(params, tx)
+	if p.MaxFeePerPayment != nil && fee.Cmp(p.MaxFeePerPayment) > 0 {
+		return nil, fmt.Errorf("fee %s exceeds maximum %s per payment", fee, p.MaxFeePerPayment)
+	}
+	return fee, nil
+}
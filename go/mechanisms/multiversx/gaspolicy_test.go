@@ -0,0 +1,67 @@
+package multiversx
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestComputeFee(t *testing.T) {
+	params := DefaultNetworkParameters("D")
+
+	// Plain transfer: everything is move balance gas
+	tx := Transaction{GasLimit: 50000, GasPrice: 1000000000}
+	if fee := ComputeFee(params, tx); fee.String() != "50000000000000" {
+		t.Errorf("Expected 0.00005 EGLD, got %s", fee)
+	}
+
+	// Token transfer: execution gas is charged at gasPrice * modifier
+	data := "MultiESDTNFTTransfer@" + bobPubKeyHex + "@01@555344432d313233@00@64"
+	tx = Transaction{Data: data, GasPrice: 1000000000}
+	tx.GasLimit = EstimateGasLimit(params, tx)
+	moveGas := int64(50000 + 1500*len(data))
+	expected := new(big.Int).Add(
+		big.NewInt(moveGas*1000000000),
+		big.NewInt(1000000*10000000),
+	)
+	if fee := ComputeFee(params, tx); fee.Cmp(expected) != 0 {
+		t.Errorf("Expected %s, got %s", expected, fee)
+	}
+
+	// Relayed: the relayer overhead is move balance gas too
+	tx = Transaction{GasLimit: 100000, GasPrice: 1000000000, Relayer: aliceAddress}
+	if fee := ComputeFee(params, tx); fee.String() != "100000000000000" {
+		t.Errorf("Expected 0.0001 EGLD, got %s", fee)
+	}
+}
+
+func TestGasPolicy_Check(t *testing.T) {
+	params := DefaultNetworkParameters("D")
+	policy := DefaultGasPolicy()
+	policy.MaxFeePerPayment = big.NewInt(200000000000000) // 0.0002 EGLD
+
+	tests := []struct {
+		name    string
+		tx      Transaction
+		wantErr string
+	}{
+		{"within limits", Transaction{GasLimit: 100000, GasPrice: 1000000000, Relayer: aliceAddress}, ""},
+		{"gas limit", Transaction{GasLimit: 600000000, GasPrice: 1000000000}, "gas limit"},
+		{"gas limit per operation", Transaction{Data: "ESDTTransfer@55@64", GasLimit: 2000000, GasPrice: 1000000000}, "ESDTTransfer"},
+		{"gas price", Transaction{GasLimit: 50000, GasPrice: 3000000000}, "gas price"},
+		{"fee", Transaction{GasLimit: 150000, GasPrice: 2000000000, Relayer: aliceAddress}, "fee"},
+	}
+
+	for _, tc := range tests {
+		fee, err := policy.Check(params, tc.tx)
+		if tc.wantErr == "" {
+			if err != nil || fee == nil {
+				t.Errorf("%s: unexpected error %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: expected %q error, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package multiversx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Public gateways for the well-known chains
+const (
+	MainnetGatewayURL = "https://gateway.multiversx.com"
+	DevnetGatewayURL  = "https://devnet-gateway.multiversx.com"
+	TestnetGatewayURL = "https://testnet-gateway.multiversx.com"
+)
+
+// DefaultGatewayURL returns the public gateway for chain IDs "1", "D" and "T".
+// Custom chains have no default.
+func DefaultGatewayURL(chainID string) (string, bool) {
+	switch chainID {
+	case "1":
+		return MainnetGatewayURL, true
+	case "D":
+		return DevnetGatewayURL, true
+	case "T":
+		return TestnetGatewayURL, true
+	}
+	return "", false
+}
+
+// GatewayProvider implements NetworkProvider on top of the MultiversX gateway REST API
+type GatewayProvider struct {
+	apiUrl string
+	client *http.Client
+
+	mu     sync.Mutex
+	config *NetworkParameters // cached: protocol parameters only change with protocol upgrades
+}
+
+// NewGatewayProvider creates a provider for the gateway at apiUrl.
+// A nil client defaults to one with a 10s timeout.
+func NewGatewayProvider(apiUrl string, client *http.Client) *GatewayProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &GatewayProvider{
+		apiUrl: strings.TrimRight(apiUrl, "/"),
+		client: client,
+	}
+}
+
+// GetNonce queries /address/{address}/nonce
+func (p *GatewayProvider) GetNonce(ctx context.Context, address string) (uint64, error) {
+	var resp struct {
+		Data struct {
+			Nonce uint64 `json:"nonce"`
+		} `json:"data"`
+	}
+	if err := p.get(ctx, "/address/"+url.PathEscape(address)+"/nonce", &resp); err != nil {
+		return 0, err
+	}
+	return resp.Data.Nonce, nil
+}
+
+// GetAccount queries /address/{address}
+func (p *GatewayProvider) GetAccount(ctx context.Context, address string) (Account, error) {
+	var resp struct {
+		Data struct {
+			Account Account `json:"account"`
+		} `json:"data"`
+	}
+	if err := p.get(ctx, "/address/"+url.PathEscape(address), &resp); err != nil {
+		return Account{}, err
+	}
+	return resp.Data.Account, nil
+}
+
+// GetNetworkConfig queries /network/config once and caches the result
+func (p *GatewayProvider) GetNetworkConfig(ctx context.Context) (NetworkParameters, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.config != nil {
+		return *p.config, nil
+	}
+
+	var resp struct {
+		Data struct {
+			Config struct {
+				ChainID               string      `json:"erd_chain_id"`
+				MinGasLimit           uint64      `json:"erd_min_gas_limit"`
+				GasPerDataByte        uint64      `json:"erd_gas_per_data_byte"`
+				MinGasPrice           uint64      `json:"erd_min_gas_price"`
+				GasPriceModifier      json.Number `json:"erd_gas_price_modifier"`
+				MinTransactionVersion uint32      `json:"erd_min_transaction_version"`
+			} `json:"config"`
+		} `json:"data"`
+	}
+	if err := p.get(ctx, "/network/config", &resp); err != nil {
+		return NetworkParameters{}, err
+	}
+
+	cfg := resp.Data.Config
+	// The gateway serves the modifier as a string ("0.01")
+	modifier, err := strconv.ParseFloat(cfg.GasPriceModifier.String(), 64)
+	if err != nil && cfg.GasPriceModifier != "" {
+		return NetworkParameters{}, fmt.Errorf("invalid gas price modifier %q", cfg.GasPriceModifier)
+	}
+
+	p.config = &NetworkParameters{
+		ChainID:               cfg.ChainID,
+		MinGasLimit:           cfg.MinGasLimit,
+		GasPerDataByte:        cfg.GasPerDataByte,
+		MinGasPrice:           cfg.MinGasPrice,
+		GasPriceModifier:      modifier,
+		MinTransactionVersion: cfg.MinTransactionVersion,
+	}
+	return *p.config, nil
+}
+
+// EstimateTransactionCost dry-runs tx through POST /transaction/cost (see TransactionCostEstimator)
+func (p *GatewayProvider) EstimateTransactionCost(ctx context.Context, tx Transaction) (uint64, error) {
+	body, err := json.Marshal(tx.GatewayRequest())
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal cost request: %v", err)
+	}
+
+	var resp struct {
+		Data struct {
+			TxGasUnits    uint64 `json:"txGasUnits"`
+			ReturnMessage string `json:"returnMessage"`
+		} `json:"data"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/transaction/cost", bytes.NewReader(body), &resp); err != nil {
+		return 0, err
+	}
+	if resp.Data.ReturnMessage != "" {
+		return 0, fmt.Errorf("transaction cost failed: %s", resp.Data.ReturnMessage)
+	}
+	return resp.Data.TxGasUnits, nil
+}
+
+// GetToken queries /esdt/{identifier} (see TokenFetcher)
+func (p *GatewayProvider) GetToken(ctx context.Context, identifier string) (TokenInfo, error) {
+	var resp struct {
+		Data struct {
+			TokenData struct {
+				Identifier string `json:"tokenIdentifier"`
+				Decimals   int    `json:"decimals"`
+			} `json:"tokenData"`
+		} `json:"data"`
+	}
+	if err := p.get(ctx, "/esdt/"+url.PathEscape(identifier), &resp); err != nil {
+		return TokenInfo{}, err
+	}
+	return TokenInfo{Identifier: resp.Data.TokenData.Identifier, Decimals: resp.Data.TokenData.Decimals}, nil
+}
+
+func (p *GatewayProvider) get(ctx context.Context, path string, out interface{}) error {
+	return p.do(ctx, http.MethodGet, path, nil, out)
+}
+
+// do performs a request and decodes the standard {data, error, code} envelope
+func (p *GatewayProvider) do(ctx context.Context, method string, path string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, p.apiUrl+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("failed to decode %s response (status %d): %v", path, resp.StatusCode, err)
+	}
+
+	var envelope struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	_ = json.Unmarshal(raw, &envelope)
+	if resp.StatusCode != http.StatusOK || envelope.Error != "" {
+		return &GatewayError{StatusCode: resp.StatusCode, Message: envelope.Error, Code: envelope.Code}
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %v", path, err)
+	}
+	return nil
+}
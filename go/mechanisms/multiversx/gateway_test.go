@@ -0,0 +1,72 @@
+package multiversx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGatewayProvider(t *testing.T) {
+	configCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/address/" + bobAddress + "/nonce":
+			w.Write([]byte(`{"data":{"nonce":42},"error":"","code":"successful"}`))
+		case "/address/" + bobAddress:
+			w.Write([]byte(`{"data":{"account":{"address":"` + bobAddress + `","nonce":42,"balance":"1000000000000000000"}},"code":"successful"}`))
+		case "/network/config":
+			configCalls++
+			w.Write([]byte(`{"data":{"config":{"erd_chain_id":"D","erd_min_gas_limit":50000,"erd_gas_per_data_byte":1500,"erd_min_gas_price":1000000000,"erd_gas_price_modifier":"0.01","erd_min_transaction_version":1}},"code":"successful"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"data":null,"error":"cannot get account: invalid address","code":"bad_request"}`))
+		}
+	}))
+	defer server.Close()
+
+	p := NewGatewayProvider(server.URL+"/", nil)
+	ctx := context.Background()
+
+	nonce, err := p.GetNonce(ctx, bobAddress)
+	if err != nil || nonce != 42 {
+		t.Errorf("Expected nonce 42, got %d (%v)", nonce, err)
+	}
+
+	account, err := p.GetAccount(ctx, bobAddress)
+	if err != nil {
+		t.Fatalf("GetAccount: %v", err)
+	}
+	if account.Nonce != 42 || account.Balance != "1000000000000000000" {
+		t.Errorf("Unexpected account: %+v", account)
+	}
+
+	for i := 0; i < 2; i++ {
+		cfg, err := p.GetNetworkConfig(ctx)
+		if err != nil {
+			t.Fatalf("GetNetworkConfig: %v", err)
+		}
+		if cfg.ChainID != "D" || cfg.MinGasLimit != 50000 || cfg.GasPerDataByte != 1500 || cfg.GasPriceModifier != 0.01 {
+			t.Errorf("Unexpected config: %+v", cfg)
+		}
+	}
+	if configCalls != 1 {
+		t.Errorf("Expected network config to be cached, got %d calls", configCalls)
+	}
+
+	_, err = p.GetNonce(ctx, "erd1invalid")
+	var gwErr *GatewayError
+	if !errors.As(err, &gwErr) || gwErr.Code != "bad_request" {
+		t.Errorf("Expected GatewayError, got %v", err)
+	}
+}
+
+func TestDefaultGatewayURL(t *testing.T) {
+	if url, ok := DefaultGatewayURL("1"); !ok || url != MainnetGatewayURL {
+		t.Errorf("Expected mainnet gateway, got %s", url)
+	}
+	if _, ok := DefaultGatewayURL("local-testnet"); ok {
+		t.Error("Custom chains must not have a default gateway")
+	}
+}
@@ -0,0 +1,170 @@
+package multiversx
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Protobuf field numbers of the node's Transaction message (mx-chain-core data/transaction)
+const (
+	protoFieldNonce             = 1
+	protoFieldValue             = 2
+	protoFieldRcvAddr           = 3
+	protoFieldRcvUserName       = 4
+	protoFieldSndAddr           = 5
+	protoFieldSndUserName       = 6
+	protoFieldGasPrice          = 7
+	protoFieldGasLimit          = 8
+	protoFieldData              = 9
+	protoFieldChainID           = 10
+	protoFieldVersion           = 11
+	protoFieldSignature         = 12
+	protoFieldOptions           = 13
+	protoFieldGuardianAddr      = 14
+	protoFieldGuardianSignature = 15
+	protoFieldRelayerAddr       = 16
+	protoFieldRelayerSignature  = 17
+)
+
+const (
+	protoWireVarint = 0
+	protoWireBytes  = 2
+)
+
+// ComputeHash returns the transaction hash as computed by the node:
+// blake2b-256 over the protobuf serialization of the signed transaction.
+func (tx *Transaction) ComputeHash() ([]byte, error) {
+	serialized, err := tx.serializeProto()
+	if err != nil {
+		return nil, err
+	}
+	hash := blake2b.Sum256(serialized)
+	return hash[:], nil
+}
+
+// Hash returns the hex encoded transaction hash, as shown by explorers and the gateway
+func (tx *Transaction) Hash() (string, error) {
+	hash, err := tx.ComputeHash()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash), nil
+}
+
+// serializeProto mirrors gogo/protobuf marshalling of the node Transaction:
+// fields in ascending order, zero values omitted, big.Int value with a sign byte.
+func (tx *Transaction) serializeProto() ([]byte, error) {
+	value, err := encodeProtoBigInt(tx.Value)
+	if err != nil {
+		return nil, err
+	}
+	receiver, err := decodeProtoAddress("receiver", tx.Receiver)
+	if err != nil {
+		return nil, err
+	}
+	sender, err := decodeProtoAddress("sender", tx.Sender)
+	if err != nil {
+		return nil, err
+	}
+	guardian, err := decodeProtoAddress("guardian", tx.Guardian)
+	if err != nil {
+		return nil, err
+	}
+	relayer, err := decodeProtoAddress("relayer", tx.Relayer)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := decodeProtoHex("signature", tx.Signature)
+	if err != nil {
+		return nil, err
+	}
+	guardianSignature, err := decodeProtoHex("guardianSignature", tx.GuardianSignature)
+	if err != nil {
+		return nil, err
+	}
+	relayerSignature, err := decodeProtoHex("relayerSignature", tx.RelayerSignature)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf []byte
+	buf = appendProtoVarint(buf, protoFieldNonce, tx.Nonce)
+	buf = appendProtoBytes(buf, protoFieldValue, value)
+	buf = appendProtoBytes(buf, protoFieldRcvAddr, receiver)
+	buf = appendProtoBytes(buf, protoFieldRcvUserName, []byte(tx.ReceiverUsername))
+	buf = appendProtoBytes(buf, protoFieldSndAddr, sender)
+	buf = appendProtoBytes(buf, protoFieldSndUserName, []byte(tx.SenderUsername))
+	buf = appendProtoVarint(buf, protoFieldGasPrice, tx.GasPrice)
+	buf = appendProtoVarint(buf, protoFieldGasLimit, tx.GasLimit)
+	buf = appendProtoBytes(buf, protoFieldData, []byte(tx.Data))
+	buf = appendProtoBytes(buf, protoFieldChainID, []byte(tx.ChainID))
+	buf = appendProtoVarint(buf, protoFieldVersion, uint64(tx.Version))
+	buf = appendProtoBytes(buf, protoFieldSignature, signature)
+	buf = appendProtoVarint(buf, protoFieldOptions, uint64(tx.Options))
+	buf = appendProtoBytes(buf, protoFieldGuardianAddr, guardian)
+	buf = appendProtoBytes(buf, protoFieldGuardianSignature, guardianSignature)
+	buf = appendProtoBytes(buf, protoFieldRelayerAddr, relayer)
+	buf = appendProtoBytes(buf, protoFieldRelayerSignature, relayerSignature)
+
+	return buf, nil
+}
+
+// encodeProtoBigInt matches the node's BigIntCaster: a sign byte followed by the magnitude.
+// Zero is encoded as {0, 0}.
+func encodeProtoBigInt(value string) ([]byte, error) {
+	if value == "" {
+		value = "0"
+	}
+	v, ok := new(big.Int).SetString(value, 10)
+	if !ok || v.Sign() < 0 {
+		return nil, fmt.Errorf("invalid value: %s", value)
+	}
+	if v.Sign() == 0 {
+		return []byte{0, 0}, nil
+	}
+	return append([]byte{0}, v.Bytes()...), nil
+}
+
+func decodeProtoAddress(field string, address string) ([]byte, error) {
+	if address == "" {
+		return nil, nil
+	}
+	_, pubKey, err := DecodeBech32(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s address: %v", field, err)
+	}
+	return pubKey, nil
+}
+
+func decodeProtoHex(field string, s string) ([]byte, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: not hex encoded", field)
+	}
+	return b, nil
+}
+
+func appendProtoKey(buf []byte, field int, wireType int) []byte {
+	return binary.AppendUvarint(buf, uint64(field<<3|wireType))
+}
+
+func appendProtoVarint(buf []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return buf
+	}
+	buf = appendProtoKey(buf, field, protoWireVarint)
+	return binary.AppendUvarint(buf, v)
+}
+
+func appendProtoBytes(buf []byte, field int, b []byte) []byte {
+	if len(b) == 0 {
+		return buf
+	}
+	buf = appendProtoKey(buf, field, protoWireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
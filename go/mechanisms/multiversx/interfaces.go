@@ -14,3 +14,25 @@ type ClientMultiversXSigner interface {
 	// For this interface, we pass the bytes to be signed.
 	Sign(ctx context.Context, message []byte) ([]byte, error)
 }
+
+// FacilitatorMultiversXSigner defines the interface for the facilitator's relayer wallet.
+// In Relayed V3 the relayer signs the same bytes as the sender and pays the gas.
+type FacilitatorMultiversXSigner interface {
+	// Address returns the bech32 address of the relayer
+	Address() string
+
+	// Sign signs the transaction bytes and returns the raw signature
+	Sign(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// NetworkProvider supplies the on-chain state a client needs to build a transaction
+type NetworkProvider interface {
+	// GetNonce returns the next nonce of the account
+	GetNonce(ctx context.Context, address string) (uint64, error)
+
+	// GetAccount returns the account state (nonce, balance)
+	GetAccount(ctx context.Context, address string) (Account, error)
+
+	// GetNetworkConfig returns the network parameters (chain ID, gas schedule)
+	GetNetworkConfig(ctx context.Context) (NetworkParameters, error)
+}
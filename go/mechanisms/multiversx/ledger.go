@@ -0,0 +1,292 @@
+package multiversx
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LedgerEntry is a payment the facilitator accepted. A signed transaction occupies the
+// (ChainID, Sender, Nonce) slot: only one transaction can ever execute with that nonce.
+type LedgerEntry struct {
+	ChainID    string    `json:"chainId"`
+	Sender     string    `json:"sender"`
+	Nonce      uint64    `json:"nonce"`
+	TxHash     string    `json:"txHash"` // as verified; the broadcast hash once settled (a relayer co-signature changes it)
+	ResourceID string    `json:"resourceId,omitempty"`
+	Settled    bool      `json:"settled"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// LedgerAccount identifies the sender of ledger entries on a chain
+type LedgerAccount struct {
+	ChainID string
+	Sender  string
+}
+
+// PaymentLedger remembers accepted payments so a signed payload cannot be used
+// for several resources, or settled twice, before it lands on chain.
+type PaymentLedger interface {
+	// Record claims the entry's slot. Until the slot is settled the same transaction may be
+	// presented again for the same resource, and a new transaction with the same nonce replaces
+	// the old one (only one of them can execute). The same transaction for another resource, or
+	// anything on a settled slot, returns a *VerificationError with InvalidReasonPaymentReused.
+	// Other errors are storage failures.
+	Record(entry LedgerEntry) error
+
+	// MarkSettled flags the slot as settled under the hash of the broadcast transaction:
+	// it can no longer be verified or settled
+	MarkSettled(chainID string, sender string, nonce uint64, txHash string) error
+
+	// Accounts lists the senders holding entries
+	Accounts() []LedgerAccount
+
+	// Prune drops the entries of an account below its on-chain nonce: those slots are used up
+	// and the node rejects any transaction reusing them
+	Prune(chainID string, sender string, accountNonce uint64) error
+}
+
+// checkReuse decides whether entry may take the slot held by existing
+func checkReuse(existing LedgerEntry, entry LedgerEntry) error {
+	switch {
+	case existing.Settled:
+		return NewVerificationError(InvalidReasonPaymentReused, "nonce %d of %s already settled in %s", entry.Nonce, entry.Sender, existing.TxHash)
+	case existing.TxHash == entry.TxHash && existing.ResourceID != entry.ResourceID:
+		return NewVerificationError(InvalidReasonPaymentReused, "transaction %s already pays for resource %q", existing.TxHash, existing.ResourceID)
+	}
+	return nil
+}
+
+type ledgerKey struct {
+	account LedgerAccount
+	nonce   uint64
+}
+
+// MemoryLedger is a PaymentLedger kept in memory, for a single facilitator process
+type MemoryLedger struct {
+	mu      sync.Mutex
+	entries map[ledgerKey]LedgerEntry
+	now     func() time.Time
+}
+
+// NewMemoryLedger creates an empty in-memory ledger
+func NewMemoryLedger() *MemoryLedger {
+	return &MemoryLedger{
+		entries: make(map[ledgerKey]LedgerEntry),
+		now:     time.Now,
+	}
+}
+
+func keyOf(chainID string, sender string, nonce uint64) ledgerKey {
+	return ledgerKey{account: LedgerAccount{ChainID: chainID, Sender: sender}, nonce: nonce}
+}
+
+func (l *MemoryLedger) Record(entry LedgerEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.recordLocked(entry)
+}
+
+func (l *MemoryLedger) recordLocked(entry LedgerEntry) error {
+	key := keyOf(entry.ChainID, entry.Sender, entry.Nonce)
+	if existing, ok := l.entries[key]; ok {
+		if err := checkReuse(existing, entry); err != nil {
+			return err
+		}
+		if existing.TxHash == entry.TxHash {
+			return nil
+		}
+	}
+	if entry.RecordedAt.IsZero() {
+		entry.RecordedAt = l.now().UTC()
+	}
+	l.entries[key] = entry
+	return nil
+}
+
+func (l *MemoryLedger) MarkSettled(chainID string, sender string, nonce uint64, txHash string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.markSettledLocked(chainID, sender, nonce, txHash)
+}
+
+func (l *MemoryLedger) markSettledLocked(chainID string, sender string, nonce uint64, txHash string) error {
+	key := keyOf(chainID, sender, nonce)
+	entry, ok := l.entries[key]
+	if !ok {
+		return fmt.Errorf("no ledger entry for nonce %d of %s on chain %s", nonce, sender, chainID)
+	}
+	entry.Settled = true
+	if txHash != "" {
+		entry.TxHash = txHash
+	}
+	l.entries[key] = entry
+	return nil
+}
+
+func (l *MemoryLedger) Accounts() []LedgerAccount {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	seen := make(map[LedgerAccount]bool)
+	var accounts []LedgerAccount
+	for key := range l.entries {
+		if !seen[key.account] {
+			seen[key.account] = true
+			accounts = append(accounts, key.account)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].ChainID != accounts[j].ChainID {
+			return accounts[i].ChainID < accounts[j].ChainID
+		}
+		return accounts[i].Sender < accounts[j].Sender
+	})
+	return accounts
+}
+
+func (l *MemoryLedger) Prune(chainID string, sender string, accountNonce uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pruneLocked(chainID, sender, accountNonce)
+	return nil
+}
+
+func (l *MemoryLedger) pruneLocked(chainID string, sender string, accountNonce uint64) {
+	account := LedgerAccount{ChainID: chainID, Sender: sender}
+	for key := range l.entries {
+		if key.account == account && key.nonce < accountNonce {
+			delete(l.entries, key)
+		}
+	}
+}
+
+// Entries returns a snapshot of every entry, ordered by account and nonce
+func (l *MemoryLedger) Entries() []LedgerEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.entriesLocked()
+}
+
+func (l *MemoryLedger) entriesLocked() []LedgerEntry {
+	entries := make([]LedgerEntry, 0, len(l.entries))
+	for _, e := range l.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.ChainID != b.ChainID {
+			return a.ChainID < b.ChainID
+		}
+		if a.Sender != b.Sender {
+			return a.Sender < b.Sender
+		}
+		return a.Nonce < b.Nonce
+	})
+	return entries
+}
+
+// FileLedger is a MemoryLedger persisted to a JSON file after every change, so accepted
+// payments survive a facilitator restart. One process must own the file.
+type FileLedger struct {
+	*MemoryLedger
+	path string
+}
+
+// NewFileLedger opens (or creates) the ledger stored at path
+func NewFileLedger(path string) (*FileLedger, error) {
+	l := &FileLedger{MemoryLedger: NewMemoryLedger(), path: path}
+
+	raw, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read ledger: %v", err)
+	}
+	if len(raw) > 0 {
+		var entries []LedgerEntry
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("invalid ledger file %s: %v", path, err)
+		}
+		for _, e := range entries {
+			l.entries[keyOf(e.ChainID, e.Sender, e.Nonce)] = e
+		}
+	}
+	return l, nil
+}
+
+func (l *FileLedger) Record(entry LedgerEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := keyOf(entry.ChainID, entry.Sender, entry.Nonce)
+	previous, existed := l.entries[key]
+	if err := l.recordLocked(entry); err != nil {
+		return err
+	}
+	if err := l.saveLocked(); err != nil {
+		// Keep memory and file in agreement
+		if existed {
+			l.entries[key] = previous
+		} else {
+			delete(l.entries, key)
+		}
+		return err
+	}
+	return nil
+}
+
+func (l *FileLedger) MarkSettled(chainID string, sender string, nonce uint64, txHash string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := keyOf(chainID, sender, nonce)
+	previous := l.entries[key]
+	if err := l.markSettledLocked(chainID, sender, nonce, txHash); err != nil {
+		return err
+	}
+	if err := l.saveLocked(); err != nil {
+		l.entries[key] = previous
+		return err
+	}
+	return nil
+}
+
+func (l *FileLedger) Prune(chainID string, sender string, accountNonce uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pruneLocked(chainID, sender, accountNonce)
+	return l.saveLocked()
+}
+
+// saveLocked writes the ledger to a temporary file and renames it over the previous one,
+// so a crash never leaves a truncated ledger behind
+func (l *FileLedger) saveLocked() error {
+	raw, err := json.MarshalIndent(l.entriesLocked(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write ledger: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write ledger: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write ledger: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write ledger: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), l.path); err != nil {
+		return fmt.Errorf("failed to write ledger: %v", err)
+	}
+	return nil
+}
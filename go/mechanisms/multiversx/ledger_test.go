@@ -0,0 +1,146 @@
+package multiversx
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func assertReused(t *testing.T, err error) {
+	t.Helper()
+	verr, ok := AsVerificationError(err)
+	if !ok || verr.Reason != InvalidReasonPaymentReused {
+		t.Errorf("Expected %s, got %v", InvalidReasonPaymentReused, err)
+	}
+}
+
+func testLedgerSemantics(t *testing.T, l PaymentLedger) {
+	entry := LedgerEntry{ChainID: "D", Sender: aliceAddress, Nonce: 5, TxHash: "aa", ResourceID: "inv_1"}
+
+	if err := l.Record(entry); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	// Verify then Settle of the same payment
+	if err := l.Record(entry); err != nil {
+		t.Errorf("Same transaction for the same resource must be accepted again: %v", err)
+	}
+
+	// Same signed payload for another resource
+	other := entry
+	other.ResourceID = "inv_2"
+	assertReused(t, l.Record(other))
+
+	// Another transaction with the same nonce replaces the unsettled one
+	replacement := entry
+	replacement.TxHash = "bb"
+	replacement.ResourceID = "inv_2"
+	if err := l.Record(replacement); err != nil {
+		t.Errorf("Replacement of an unsettled nonce must be accepted: %v", err)
+	}
+
+	// Settled slots are closed for good
+	if err := l.MarkSettled("D", aliceAddress, 5, ""); err != nil {
+		t.Fatalf("MarkSettled failed: %v", err)
+	}
+	assertReused(t, l.Record(replacement))
+	assertReused(t, l.Record(entry))
+
+	// Same nonce on another chain is another slot
+	testnet := entry
+	testnet.ChainID = "T"
+	if err := l.Record(testnet); err != nil {
+		t.Errorf("Other chain must not conflict: %v", err)
+	}
+	if err := l.MarkSettled("D", aliceAddress, 6, ""); err == nil {
+		t.Error("Expected an error when settling an unknown slot")
+	}
+
+	// Chain nonce passed the entry: the slot is gone
+	accounts := l.Accounts()
+	expected := []LedgerAccount{{ChainID: "D", Sender: aliceAddress}, {ChainID: "T", Sender: aliceAddress}}
+	if !reflect.DeepEqual(accounts, expected) {
+		t.Errorf("Expected accounts %v, got %v", expected, accounts)
+	}
+	if err := l.Prune("D", aliceAddress, 5); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	assertReused(t, l.Record(entry)) // nonce 5 not passed yet
+	if err := l.Prune("D", aliceAddress, 6); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if accounts := l.Accounts(); len(accounts) != 1 || accounts[0].ChainID != "T" {
+		t.Errorf("Expected only the testnet account left, got %v", accounts)
+	}
+}
+
+func TestMemoryLedger(t *testing.T) {
+	testLedgerSemantics(t, NewMemoryLedger())
+}
+
+func TestFileLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.json")
+	l, err := NewFileLedger(path)
+	if err != nil {
+		t.Fatalf("NewFileLedger failed: %v", err)
+	}
+	testLedgerSemantics(t, l)
+
+	// A restarted facilitator still knows the settled payment
+	settled := LedgerEntry{ChainID: "D", Sender: bobAddress, Nonce: 1, TxHash: "cc", ResourceID: "inv_3"}
+	if err := l.Record(settled); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := l.MarkSettled("D", bobAddress, 1, ""); err != nil {
+		t.Fatalf("MarkSettled failed: %v", err)
+	}
+
+	reopened, err := NewFileLedger(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	if !reflect.DeepEqual(reopened.Entries(), l.Entries()) {
+		t.Errorf("Reopened ledger differs:\n%+v\n%+v", reopened.Entries(), l.Entries())
+	}
+	assertReused(t, reopened.Record(settled))
+
+	// No temporary files left behind
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*"))
+	if len(files) != 1 {
+		t.Errorf("Expected only the ledger file, got %v", files)
+	}
+
+	// Corrupted ledgers are not silently reset
+	os.WriteFile(path, []byte("{not json"), 0o600)
+	if _, err := NewFileLedger(path); err == nil {
+		t.Error("Expected an error for a corrupted ledger file")
+	}
+}
+
+func TestFileLedger_SaveFailure(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "ledger")
+	os.Mkdir(dir, 0o700)
+	l, err := NewFileLedger(filepath.Join(dir, "ledger.json"))
+	if err != nil {
+		t.Fatalf("NewFileLedger failed: %v", err)
+	}
+	entry := LedgerEntry{ChainID: "D", Sender: bobAddress, Nonce: 1, TxHash: "aa", ResourceID: "inv_1"}
+	if err := l.Record(entry); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	// The file can no longer be written: memory must not run ahead of it
+	os.RemoveAll(dir)
+	if err := l.MarkSettled("D", bobAddress, 1, ""); err == nil {
+		t.Fatal("Expected MarkSettled to fail")
+	}
+	if entries := l.Entries(); len(entries) != 1 || entries[0].Settled {
+		t.Errorf("Expected the entry to stay unsettled, got %+v", entries)
+	}
+	if err := l.Record(LedgerEntry{ChainID: "D", Sender: bobAddress, Nonce: 2, TxHash: "bb"}); err == nil {
+		t.Fatal("Expected Record to fail")
+	}
+	if len(l.Entries()) != 1 {
+		t.Errorf("Expected the failed record to be rolled back, got %+v", l.Entries())
+	}
+}
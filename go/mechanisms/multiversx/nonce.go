@@ -0,0 +1,150 @@
+package multiversx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// NonceManager hands out transaction nonces per sender without a gateway round trip per payment.
+// Many payments can be built in parallel from one address: each Reserve returns a distinct nonce.
+// The first Reserve for an address (and every Resync) reads the account nonce from the network.
+type NonceManager struct {
+	provider NetworkProvider
+
+	mu       sync.Mutex
+	accounts map[string]*accountNonces
+}
+
+// accountNonces is the local nonce state of one sender
+type accountNonces struct {
+	mu       sync.Mutex
+	synced   bool
+	next     uint64   // next never-reserved nonce
+	released []uint64 // reserved then abandoned, below next, ascending
+}
+
+// NewNonceManager creates a manager reading account nonces from provider
+func NewNonceManager(provider NetworkProvider) *NonceManager {
+	return &NonceManager{
+		provider: provider,
+		accounts: make(map[string]*accountNonces),
+	}
+}
+
+func (m *NonceManager) account(address string) *accountNonces {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc, ok := m.accounts[address]
+	if !ok {
+		acc = &accountNonces{}
+		m.accounts[address] = acc
+	}
+	return acc
+}
+
+// Reserve returns a nonce no other caller holds. Abandoned nonces are reused first
+// so the account does not end up with a gap that blocks later transactions.
+func (m *NonceManager) Reserve(ctx context.Context, address string) (uint64, error) {
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.synced {
+		if err := m.sync(ctx, address, acc, false); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(acc.released) > 0 {
+		nonce := acc.released[0]
+		acc.released = acc.released[1:]
+		return nonce, nil
+	}
+
+	nonce := acc.next
+	acc.next++
+	return nonce, nil
+}
+
+// Release returns a reserved nonce whose transaction will never be broadcast
+func (m *NonceManager) Release(address string, nonce uint64) {
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.synced || nonce >= acc.next {
+		return
+	}
+
+	idx := sort.Search(len(acc.released), func(i int) bool { return acc.released[i] >= nonce })
+	if idx < len(acc.released) && acc.released[idx] == nonce {
+		return
+	}
+	acc.released = append(acc.released, 0)
+	copy(acc.released[idx+1:], acc.released[idx:])
+	acc.released[idx] = nonce
+
+	// Shrink back when the highest nonces were all released
+	for n := len(acc.released); n > 0 && acc.released[n-1] == acc.next-1; n-- {
+		acc.released = acc.released[:n-1]
+		acc.next--
+	}
+}
+
+// Resync reloads the nonce of the address from the network. Nonces the account has
+// already used are dropped; nonces still reserved by in-flight payloads stay reserved.
+// Call it when the gateway rejects a transaction with a nonce error.
+func (m *NonceManager) Resync(ctx context.Context, address string) error {
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	return m.sync(ctx, address, acc, false)
+}
+
+// HandleError resyncs the address if reason is a nonce error (see IsNonceError).
+// A nonce too high means the network never saw our lower nonces: the local state
+// rewinds to the account nonce. It reports whether a resync happened.
+func (m *NonceManager) HandleError(ctx context.Context, address string, reason string) (bool, error) {
+	if !IsNonceError(reason) {
+		return false, nil
+	}
+	code := ReasonCode(reason)
+	if code != SettleReasonNonceTooLow && code != SettleReasonNonceTooHigh {
+		code = ClassifySendError(reason)
+	}
+
+	acc := m.account(address)
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	return true, m.sync(ctx, address, acc, code == SettleReasonNonceTooHigh)
+}
+
+// sync must be called with acc.mu held. Without rewind, next only moves forward
+// while reservations at or above the account nonce are outstanding.
+func (m *NonceManager) sync(ctx context.Context, address string, acc *accountNonces, rewind bool) error {
+	nonce, err := m.provider.GetNonce(ctx, address)
+	if err != nil {
+		acc.synced = false
+		return fmt.Errorf("failed to fetch nonce for %s: %v", address, err)
+	}
+	if rewind || nonce >= acc.next || !acc.outstanding(nonce) {
+		acc.next = nonce
+		acc.released = nil
+	} else {
+		// Released nonces below the account nonce were used by someone else
+		idx := sort.Search(len(acc.released), func(i int) bool { return acc.released[i] >= nonce })
+		acc.released = acc.released[idx:]
+	}
+	acc.synced = true
+	return nil
+}
+
+// outstanding reports whether a nonce in [from, next) is reserved and not released
+func (acc *accountNonces) outstanding(from uint64) bool {
+	idx := sort.Search(len(acc.released), func(i int) bool { return acc.released[i] >= from })
+	return acc.next-from > uint64(len(acc.released)-idx)
+}
@@ -0,0 +1,181 @@
+package multiversx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// stubNonceProvider serves a settable account nonce and counts queries
+type stubNonceProvider struct {
+	nonce atomic.Uint64
+	calls atomic.Int32
+	err   error
+}
+
+func (p *stubNonceProvider) GetNonce(ctx context.Context, address string) (uint64, error) {
+	p.calls.Add(1)
+	return p.nonce.Load(), p.err
+}
+
+func (p *stubNonceProvider) GetAccount(ctx context.Context, address string) (Account, error) {
+	return Account{Address: address, Nonce: p.nonce.Load()}, p.err
+}
+
+func (p *stubNonceProvider) GetNetworkConfig(ctx context.Context) (NetworkParameters, error) {
+	return NetworkParameters{}, p.err
+}
+
+func TestNonceManager_ConcurrentReserve(t *testing.T) {
+	provider := &stubNonceProvider{}
+	provider.nonce.Store(100)
+	m := NewNonceManager(provider)
+
+	const workers = 500
+	nonces := make(chan uint64, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := m.Reserve(context.Background(), bobAddress)
+			if err != nil {
+				t.Errorf("Reserve: %v", err)
+				return
+			}
+			nonces <- n
+		}()
+	}
+	wg.Wait()
+	close(nonces)
+
+	seen := make(map[uint64]bool)
+	for n := range nonces {
+		if seen[n] {
+			t.Fatalf("Nonce %d reserved twice", n)
+		}
+		if n < 100 || n >= 100+workers {
+			t.Errorf("Nonce %d out of range", n)
+		}
+		seen[n] = true
+	}
+	if len(seen) != workers {
+		t.Errorf("Expected %d nonces, got %d", workers, len(seen))
+	}
+	if calls := provider.calls.Load(); calls != 1 {
+		t.Errorf("Expected a single network fetch, got %d", calls)
+	}
+}
+
+func TestNonceManager_Release(t *testing.T) {
+	provider := &stubNonceProvider{}
+	provider.nonce.Store(7)
+	m := NewNonceManager(provider)
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		m.Reserve(ctx, bobAddress) // 7, 8, 9, 10
+	}
+
+	// A hole in the middle is filled first
+	m.Release(bobAddress, 8)
+	m.Release(bobAddress, 8) // double release is ignored
+	if n, _ := m.Reserve(ctx, bobAddress); n != 8 {
+		t.Errorf("Expected released nonce 8, got %d", n)
+	}
+
+	// Releasing the tail rewinds the counter
+	m.Release(bobAddress, 9)
+	m.Release(bobAddress, 10)
+	if n, _ := m.Reserve(ctx, bobAddress); n != 9 {
+		t.Errorf("Expected 9 after releasing the tail, got %d", n)
+	}
+	if n, _ := m.Reserve(ctx, bobAddress); n != 10 {
+		t.Errorf("Expected 10, got %d", n)
+	}
+	if n, _ := m.Reserve(ctx, bobAddress); n != 11 {
+		t.Errorf("Expected 11, got %d", n)
+	}
+
+	// Never reserved: ignored
+	m.Release(bobAddress, 50)
+	if n, _ := m.Reserve(ctx, bobAddress); n != 12 {
+		t.Errorf("Expected 12, got %d", n)
+	}
+}
+
+func TestNonceManager_HandleError(t *testing.T) {
+	provider := &stubNonceProvider{}
+	provider.nonce.Store(3)
+	m := NewNonceManager(provider)
+	ctx := context.Background()
+
+	m.Reserve(ctx, bobAddress)
+	m.Reserve(ctx, bobAddress)
+
+	// Someone else spent nonces from the same account
+	provider.nonce.Store(20)
+
+	resynced, err := m.HandleError(ctx, bobAddress, FormatReason(SettleReasonInsufficientFunds, "insufficient funds"))
+	if resynced || err != nil {
+		t.Errorf("Non-nonce error must not resync (resynced=%v, err=%v)", resynced, err)
+	}
+
+	resynced, err = m.HandleError(ctx, bobAddress, "transaction generation failed: lowerNonceInTx: lower nonce in transaction")
+	if !resynced || err != nil {
+		t.Fatalf("Expected resync, got resynced=%v err=%v", resynced, err)
+	}
+	if n, _ := m.Reserve(ctx, bobAddress); n != 20 {
+		t.Errorf("Expected nonce 20 after resync, got %d", n)
+	}
+
+	provider.err = errors.New("gateway down")
+	if err := m.Resync(ctx, bobAddress); err == nil {
+		t.Error("Expected resync error")
+	}
+	if _, err := m.Reserve(ctx, bobAddress); err == nil {
+		t.Error("Expected Reserve to retry the failed sync")
+	}
+}
+
+func TestNonceManager_ResyncKeepsReservations(t *testing.T) {
+	provider := &stubNonceProvider{}
+	provider.nonce.Store(10)
+	m := NewNonceManager(provider)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		m.Reserve(ctx, bobAddress) // 10..14 held by in-flight payloads
+	}
+	m.Release(bobAddress, 12)
+
+	// 10 went through, 11 is rejected as too low: 11..14 must not be handed out again
+	provider.nonce.Store(11)
+	if _, err := m.HandleError(ctx, bobAddress, FormatReason(SettleReasonNonceTooLow, "lower nonce in transaction")); err != nil {
+		t.Fatalf("HandleError: %v", err)
+	}
+	for _, expected := range []uint64{12, 15} {
+		if n, _ := m.Reserve(ctx, bobAddress); n != expected {
+			t.Errorf("Expected %d, got %d", expected, n)
+		}
+	}
+
+	// A plain resync behaves the same
+	if err := m.Resync(ctx, bobAddress); err != nil {
+		t.Fatalf("Resync: %v", err)
+	}
+	if n, _ := m.Reserve(ctx, bobAddress); n != 16 {
+		t.Errorf("Expected 16, got %d", n)
+	}
+
+	// Too high: the network never saw our nonces, start over from the account nonce
+	if _, err := m.HandleError(ctx, bobAddress, FormatReason(SettleReasonNonceTooHigh, "higher nonce in transaction")); err != nil {
+		t.Fatalf("HandleError: %v", err)
+	}
+	if n, _ := m.Reserve(ctx, bobAddress); n != 11 {
+		t.Errorf("Expected 11 after rewind, got %d", n)
+	}
+
+}
@@ -0,0 +1,197 @@
+package multiversx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/x402/go/types"
+)
+
+// EGLDTokenIdentifier stands for EGLD inside MultiESDTNFTTransfer
+const EGLDTokenIdentifier = "EGLD-000000"
+
+// ExtraKeyAssets lists further {"asset", "amount"} pairs paid together with Asset/Amount
+// in one MultiESDTNFTTransfer (bundle prices)
+const ExtraKeyAssets = "assets"
+
+// RequiredTransfers returns the legs a payment must contain: Asset/Amount first, then every
+// entry of Extra["assets"]. A lone EGLD leg keeps the identifier "EGLD" (plain value transfer);
+// in a bundle EGLD is expressed as EGLD-000000.
+func RequiredTransfers(requirements types.PaymentRequirements) ([]TokenTransfer, error) {
+	type assetAmount struct {
+		Asset  string `json:"asset"`
+		Amount string `json:"amount"`
+	}
+	entries := []assetAmount{{Asset: requirements.Asset, Amount: requirements.Amount}}
+	if raw, ok := requirements.Extra[ExtraKeyAssets]; ok && raw != nil {
+		// Round-trip through JSON: Extra holds []interface{} when decoded, typed slices when built in Go
+		rawBytes, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", ExtraKeyAssets, err)
+		}
+		var extra []assetAmount
+		if err := json.Unmarshal(rawBytes, &extra); err != nil {
+			return nil, fmt.Errorf("invalid %s: expected a list of asset/amount pairs", ExtraKeyAssets)
+		}
+		for i, e := range extra {
+			if e.Asset == "" {
+				return nil, fmt.Errorf("%s[%d]: asset is required", ExtraKeyAssets, i)
+			}
+		}
+		entries = append(entries, extra...)
+	}
+
+	legs := make([]TokenTransfer, 0, len(entries))
+	for i, e := range entries {
+		if e.Amount == "" {
+			return nil, fmt.Errorf("amount is required for %s", e.Asset)
+		}
+		amount, ok := new(big.Int).SetString(e.Amount, 10)
+		if !ok || amount.Sign() < 0 || (i > 0 && amount.Sign() == 0) {
+			return nil, fmt.Errorf("invalid amount %s for %s", e.Amount, e.Asset)
+		}
+
+		asset := e.Asset
+		if asset == "" {
+			asset = "EGLD"
+		}
+		if asset == "EGLD" && len(entries) > 1 {
+			asset = EGLDTokenIdentifier
+		}
+		if asset == "EGLD" {
+			legs = append(legs, TokenTransfer{Identifier: asset, Amount: amount})
+			continue
+		}
+
+		token, err := ParseTokenIdentifier(asset)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range legs {
+			if l.Identifier == token.Collection && l.Nonce == token.Nonce {
+				return nil, fmt.Errorf("asset %s is listed twice", asset)
+			}
+		}
+		legs = append(legs, TokenTransfer{Identifier: token.Collection, Nonce: token.Nonce, Amount: amount})
+	}
+	return legs, nil
+}
+
+// ChainIDFromNetwork resolves a network ("multiversx:<ref>" or an alias such as "mainnet")
+// to its chain ID like GetMultiversXChainId. Empty defaults to devnet.
+func ChainIDFromNetwork(network string) (string, error) {
+	if network == "" {
+		return "D", nil // Default Devnet
+	}
+	return GetMultiversXChainId(network)
+}
+
+// TransferFormat selects how token payments are laid out on-chain
+type TransferFormat int
+
+const (
+	// TransferFormatMultiESDT is a MultiESDTNFTTransfer self-transfer naming PayTo in the data (default)
+	TransferFormatMultiESDT TransferFormat = iota
+	// TransferFormatESDTTransfer is an ESDTTransfer sent directly to PayTo, as most wallets produce
+	TransferFormatESDTTransfer
+)
+
+// NewPaymentTransaction builds the unsigned transfer that pays requirements from sender.
+// Nonce, gas price and gas limit are left to the caller.
+func NewPaymentTransaction(requirements types.PaymentRequirements, sender string, format TransferFormat) (Transaction, error) {
+	// 1. Validate inputs
+	if requirements.PayTo == "" {
+		return Transaction{}, fmt.Errorf("PayTo is required")
+	}
+	payTo, err := AddressFromBech32(requirements.PayTo)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("invalid PayTo: %v", err)
+	}
+
+	chainID, err := ChainIDFromNetwork(string(requirements.Network))
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	tx := Transaction{
+		Value:    requirements.Amount,
+		Receiver: payTo.Bech32(),
+		Sender:   sender,
+		ChainID:  chainID,
+		Version:  1,
+	}
+
+	// 2. ESDT Logic
+	legs, err := RequiredTransfers(requirements)
+	if err != nil {
+		return Transaction{}, err
+	}
+	binding, err := RequiredBinding(requirements)
+	if err != nil {
+		return Transaction{}, err
+	}
+	if legs[0].Identifier == "EGLD" && !binding.IsEmpty() {
+		// Plain transfer: the binding is the whole data field
+		tx.Data = binding.EGLDData()
+	}
+	if legs[0].Identifier != "EGLD" {
+		tx.Value = "0"
+
+		var transfer TransferData
+		leg := legs[0]
+		switch {
+		case len(legs) > 1:
+			// Bundles only exist as MultiESDTNFTTransfer, whatever the preferred format
+			tx.Receiver = sender
+			transfer = TransferData{
+				Function:  FunctionMultiESDTNFTTransfer,
+				Receiver:  payTo,
+				Transfers: legs,
+			}
+		case format == TransferFormatESDTTransfer && leg.Nonce > 0:
+			// ESDTNFTTransfer@<CollectionHex>@<NonceHex>@<QuantityHex>@<DestHex>[@<ResourceIdHex>]
+			// Receiver becomes Sender (Self Transfer), as wallets send NFTs/SFTs.
+			tx.Receiver = sender
+			transfer = TransferData{
+				Function:  FunctionESDTNFTTransfer,
+				Receiver:  payTo,
+				Transfers: legs,
+			}
+		case format == TransferFormatESDTTransfer && leg.Identifier != EGLDTokenIdentifier:
+			// ESDTTransfer@<TokenHex>@<AmountHex>[@<ResourceIdHex>], straight to PayTo
+			transfer = TransferData{
+				Function:  FunctionESDTTransfer,
+				Transfers: legs,
+			}
+		default:
+			// MultiESDTNFTTransfer@<DestHex>@01@<TokenHex>@<NonceHex>@<AmountHex>[@<ResourceIdHex>]
+			// Receiver becomes Sender (Self Transfer). EGLD-000000 only exists in this form.
+			tx.Receiver = sender
+			transfer = TransferData{
+				Function:  FunctionMultiESDTNFTTransfer,
+				Receiver:  payTo,
+				Transfers: legs,
+			}
+		}
+
+		// ResourceID in the function slot after the transfers, expiry as its argument
+		transfer.CallFunction, transfer.CallArgs = binding.ResourceID, binding.callArgs()
+
+		tx.Data, err = transfer.Encode()
+		if err != nil {
+			return Transaction{}, err
+		}
+	}
+
+	// 3. Relayed V3: the relayer advertised by the facilitator pays the gas
+	if r, ok := requirements.Extra[ExtraKeyRelayer].(string); ok && r != "" {
+		if !IsValidAddressWithHRP(r, payTo.HRP()) {
+			return Transaction{}, fmt.Errorf("invalid relayer address: %s", r)
+		}
+		tx.Relayer = r
+		tx.Version = RelayedTransactionVersion
+	}
+
+	return tx, nil
+}
@@ -0,0 +1,121 @@
+package multiversx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Verification failure reasons reported in x402.VerifyResponse.InvalidReason.
+// They describe a bad payment; infrastructure failures are returned as Go errors instead.
+const (
+	InvalidReasonInvalidPayload      = "invalid_payload"
+	InvalidReasonInvalidRequirements = "invalid_requirements"
+	InvalidReasonInvalidSignature    = "invalid_signature"
+	InvalidReasonReceiverMismatch    = "receiver_mismatch"
+	InvalidReasonInsufficientAmount  = "insufficient_amount"
+	InvalidReasonQuantityMismatch    = "quantity_mismatch"
+	InvalidReasonAssetMismatch       = "asset_mismatch"
+	InvalidReasonResourceMismatch    = "resource_mismatch"
+	InvalidReasonChainMismatch       = "chain_mismatch"
+	InvalidReasonUnsupportedNetwork  = "unsupported_network"
+	InvalidReasonRelayerMismatch     = "relayer_mismatch"
+	InvalidReasonGasPolicy           = "gas_policy_exceeded"
+	InvalidReasonSimulationFailed    = "simulation_failed"
+	InvalidReasonNonceStale          = "nonce_stale"
+	InvalidReasonPaymentReused       = "payment_reused"
+	InvalidReasonPaymentExpired      = "payment_expired"
+)
+
+// VerificationError is a payment rejection carrying one of the InvalidReason codes
+type VerificationError struct {
+	Reason string
+	Detail string
+}
+
+func (e *VerificationError) Error() string {
+	return FormatReason(e.Reason, e.Detail)
+}
+
+// NewVerificationError builds a rejection with a formatted detail
+func NewVerificationError(reason string, format string, args ...interface{}) *VerificationError {
+	return &VerificationError{Reason: reason, Detail: fmt.Sprintf(format, args...)}
+}
+
+// AsVerificationError reports whether err is (or wraps) a payment rejection
+func AsVerificationError(err error) (*VerificationError, bool) {
+	var verr *VerificationError
+	if errors.As(err, &verr) {
+		return verr, true
+	}
+	return nil, false
+}
+
+// Settlement failure reasons reported in x402.SettleResponse.ErrorReason.
+// When more context is available (e.g. the gateway message) it is appended
+// after the code as "<code>: <detail>".
+const (
+	SettleReasonInvalidPayload      = "invalid_payload"
+	SettleReasonUnsupportedNetwork  = "unsupported_network"
+	SettleReasonVerificationFailed  = "verification_failed"
+	SettleReasonPaymentExpired      = "payment_expired"
+	SettleReasonNonceTooLow         = "nonce_too_low"
+	SettleReasonNonceTooHigh        = "nonce_too_high"
+	SettleReasonInsufficientFunds   = "insufficient_funds"
+	SettleReasonTransactionRejected = "transaction_rejected"
+	SettleReasonTransactionFailed   = "transaction_failed"
+	SettleReasonFinalityTimeout     = "finality_timeout"
+)
+
+// FormatReason joins a reason code with an optional human readable detail
+func FormatReason(code string, detail string) string {
+	if detail == "" {
+		return code
+	}
+	return code + ": " + detail
+}
+
+// ReasonCode extracts the stable code from a reason built by FormatReason
+func ReasonCode(reason string) string {
+	if idx := strings.Index(reason, ":"); idx >= 0 {
+		return reason[:idx]
+	}
+	return reason
+}
+
+// ClassifySendError maps a gateway rejection message from /transaction/send
+// to a settlement failure reason code.
+func ClassifySendError(message string) string {
+	msg := strings.ToLower(message)
+	switch {
+	case strings.Contains(msg, "lower nonce"), strings.Contains(msg, "nonce too low"):
+		return SettleReasonNonceTooLow
+	case strings.Contains(msg, "higher nonce"), strings.Contains(msg, "nonce too high"):
+		return SettleReasonNonceTooHigh
+	case strings.Contains(msg, "insufficient funds"), strings.Contains(msg, "insufficient balance"):
+		return SettleReasonInsufficientFunds
+	default:
+		return SettleReasonTransactionRejected
+	}
+}
+
+// IsNonceError reports whether a settlement reason (or a raw gateway message)
+// means the transaction nonce is out of sync with the account.
+func IsNonceError(reason string) bool {
+	switch ReasonCode(reason) {
+	case SettleReasonNonceTooLow, SettleReasonNonceTooHigh:
+		return true
+	}
+	code := ClassifySendError(reason)
+	return code == SettleReasonNonceTooLow || code == SettleReasonNonceTooHigh
+}
+
+// IsBroadcastReason reports whether a failed settlement still reached the chain.
+// Such transactions consume their nonce; all other failures leave it unused.
+func IsBroadcastReason(reason string) bool {
+	switch ReasonCode(reason) {
+	case SettleReasonTransactionFailed, SettleReasonFinalityTimeout:
+		return true
+	}
+	return false
+}
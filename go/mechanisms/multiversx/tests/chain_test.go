@@ -0,0 +1,91 @@
+package multiversx_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"x402-integration/mechanisms/multiversx"
+	"x402-integration/mechanisms/multiversx/exact/facilitator"
+
+	"github.com/coinbase/x402/go/types"
+)
+
+func chainFixture(t *testing.T, chainID string) types.PaymentPayload {
+	rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+	rp.Data.Receiver = testPayTo
+	rp.Data.Sender = testSender
+	rp.Data.Value = "100"
+	rp.Data.ChainID = chainID
+	rp.Data.Version = 1
+	signTransaction(t, &rp.Data)
+	return toPaymentPayload(rp)
+}
+
+func TestFacilitatorCheckGateway(t *testing.T) {
+	server := newPolicyGatewayStub(t) // serves chain "D"
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+	if scheme.ChainID() != "" {
+		t.Fatalf("Chain ID must be unknown before CheckGateway, got %s", scheme.ChainID())
+	}
+	if err := scheme.CheckGateway(context.Background()); err != nil {
+		t.Fatalf("CheckGateway failed: %v", err)
+	}
+	if scheme.ChainID() != "D" {
+		t.Errorf("Expected cached chain D, got %s", scheme.ChainID())
+	}
+
+	// Mainnet facilitator pointed at a devnet gateway
+	misconfigured := facilitator.NewExactMultiversXScheme(server.URL, facilitator.WithChainID("1"))
+	if err := misconfigured.CheckGateway(context.Background()); err == nil {
+		t.Error("Expected CheckGateway to detect the chain mismatch")
+	}
+
+	unreachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unreachable.Close()
+	if err := facilitator.NewExactMultiversXScheme(unreachable.URL).CheckGateway(context.Background()); err == nil {
+		t.Error("Expected CheckGateway to fail on an unreachable gateway")
+	}
+}
+
+func TestFacilitatorVerify_ChainID(t *testing.T) {
+	server := newPolicyGatewayStub(t)
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+	if err := scheme.CheckGateway(context.Background()); err != nil {
+		t.Fatalf("CheckGateway failed: %v", err)
+	}
+	req := types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD", Network: "multiversx:D"}
+
+	resp, err := scheme.Verify(context.Background(), chainFixture(t, "D"), req)
+	if err != nil || !resp.IsValid {
+		t.Fatalf("Expected devnet payload to be valid, got %+v (%v)", resp, err)
+	}
+
+	// Mainnet-signed payload against a devnet requirement
+	resp, err = scheme.Verify(context.Background(), chainFixture(t, "1"), req)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonChainMismatch)
+
+	// Without a network in the requirements the gateway's chain is expected
+	noNetwork := req
+	noNetwork.Network = ""
+	resp, err = scheme.Verify(context.Background(), chainFixture(t, "T"), noNetwork)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonChainMismatch)
+
+	// Requirements for a chain the gateway does not serve cannot be verified here
+	mainnet := req
+	mainnet.Network = "multiversx:1"
+	if _, err := scheme.Verify(context.Background(), chainFixture(t, "1"), mainnet); err == nil {
+		t.Error("Expected an error for requirements on another chain than the gateway")
+	}
+
+	// Unsupported network format
+	bad := req
+	bad.Network = "eip155:1"
+	resp, err = scheme.Verify(context.Background(), chainFixture(t, "D"), bad)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonInvalidRequirements)
+}
@@ -0,0 +1,158 @@
+package multiversx_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"x402-integration/mechanisms/multiversx"
+	"x402-integration/mechanisms/multiversx/exact/facilitator"
+
+	"github.com/coinbase/x402/go/types"
+)
+
+// newPolicyGatewayStub serves network config, simulation and send
+func newPolicyGatewayStub(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/network/config":
+			w.Write([]byte(`{"data":{"config":{"erd_chain_id":"D","erd_min_gas_limit":50000,"erd_gas_per_data_byte":1500,"erd_min_gas_price":1000000000,"erd_gas_price_modifier":"0.01"}},"code":"successful"}`))
+		case "/transaction/simulate":
+			writeSimulationSuccess(t, w, r)
+		case "/transaction/send":
+			resp := multiversx.SendTransactionResponse{}
+			resp.Data.TxHash = "policy_hash"
+			json.NewEncoder(w).Encode(resp)
+		}
+	}))
+}
+
+func relayedGasFixture(t *testing.T, nonce uint64, gasLimit uint64, gasPrice uint64, relayer string) (types.PaymentPayload, types.PaymentRequirements) {
+	rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+	rp.Data.Receiver = testPayTo
+	rp.Data.Sender = testSender
+	rp.Data.Value = "100"
+	rp.Data.Nonce = nonce
+	rp.Data.GasLimit = gasLimit
+	rp.Data.GasPrice = gasPrice
+	rp.Data.ChainID = "D"
+	rp.Data.Version = multiversx.RelayedTransactionVersion
+	rp.Data.Relayer = relayer
+	signTransaction(t, &rp.Data)
+
+	return toPaymentPayload(rp), types.PaymentRequirements{
+		PayTo:   testPayTo,
+		Amount:  "100",
+		Asset:   "EGLD",
+		Network: "multiversx:D",
+	}
+}
+
+func TestFacilitatorGasPolicy_Caps(t *testing.T) {
+	server := newPolicyGatewayStub(t)
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL,
+		facilitator.WithRelayerSigner(&mockRelayer{addr: testRelayer}),
+		facilitator.WithGasPolicy(multiversx.DefaultGasPolicy()),
+	)
+
+	tests := []struct {
+		name     string
+		gasLimit uint64
+		gasPrice uint64
+		valid    bool
+	}{
+		{"within policy", 100000, 1000000000, true},
+		{"drain attempt", 600000000, 1000000000, false},
+		{"overpriced", 100000, 10000000000, false},
+	}
+
+	for _, tc := range tests {
+		payload, req := relayedGasFixture(t, 1, tc.gasLimit, tc.gasPrice, testRelayer)
+		resp, err := scheme.Verify(context.Background(), payload, req)
+		if tc.valid {
+			if err != nil || !resp.IsValid {
+				t.Errorf("%s: expected valid payment, got %+v (%v)", tc.name, resp, err)
+			}
+			continue
+		}
+		assertInvalid(t, resp, err, multiversx.InvalidReasonGasPolicy)
+	}
+
+	// The sender pays its own gas: not our policy's business
+	rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+	rp.Data.Receiver = testPayTo
+	rp.Data.Sender = testSender
+	rp.Data.Value = "100"
+	rp.Data.GasLimit = 600000000
+	rp.Data.GasPrice = 1000000000
+	rp.Data.ChainID = "D"
+	rp.Data.Version = 1
+	signTransaction(t, &rp.Data)
+	_, req := relayedGasFixture(t, 1, 0, 0, testRelayer)
+	if resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), req); err != nil || !resp.IsValid {
+		t.Errorf("Non-relayed payload should not be subject to the gas policy: %+v (%v)", resp, err)
+	}
+}
+
+func TestFacilitatorGasPolicy_Window(t *testing.T) {
+	server := newPolicyGatewayStub(t)
+	defer server.Close()
+
+	// One relayed transfer costs 0.0001 EGLD; allow 1.5 of them per hour
+	policy := multiversx.DefaultGasPolicy()
+	policy.MaxFeePerWindow = big.NewInt(150000000000000)
+	policy.Window = time.Hour
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL,
+		facilitator.WithRelayerSigner(&mockRelayer{addr: testRelayer}),
+		facilitator.WithGasPolicy(policy),
+	)
+
+	first, req := relayedGasFixture(t, 1, 100000, 1000000000, testRelayer)
+	resp, err := scheme.Settle(context.Background(), first, req)
+	if err != nil || !resp.Success {
+		t.Fatalf("Expected first settlement to succeed, got %+v (%v)", resp, err)
+	}
+
+	second, req := relayedGasFixture(t, 2, 100000, 1000000000, testRelayer)
+	verifyResp, err := scheme.Verify(context.Background(), second, req)
+	assertInvalid(t, verifyResp, err, multiversx.InvalidReasonGasPolicy)
+
+	resp, err = scheme.Settle(context.Background(), second, req)
+	if err != nil {
+		t.Fatalf("Settle: %v", err)
+	}
+	if resp.Success || multiversx.ReasonCode(resp.ErrorReason) != multiversx.SettleReasonVerificationFailed || !strings.Contains(resp.ErrorReason, "budget") {
+		t.Errorf("Expected verification_failed, got %+v", resp)
+	}
+}
+
+func TestFacilitatorGasPolicy_DefaultForRelayer(t *testing.T) {
+	server := newPolicyGatewayStub(t)
+	defer server.Close()
+
+	// 600M gas at the minimum price: the relayer would pay 6 EGLD for a transfer
+	payload, req := relayedGasFixture(t, 1, 600_000_000, 1_000_000_000, testRelayer)
+
+	// A relayer is capped even without WithGasPolicy
+	scheme := facilitator.NewExactMultiversXScheme(server.URL, facilitator.WithRelayerSigner(&mockRelayer{addr: testRelayer}))
+	resp, err := scheme.Verify(context.Background(), payload, req)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonGasPolicy)
+
+	// Lifting the caps takes an explicit opt-out
+	unbounded := facilitator.NewExactMultiversXScheme(server.URL,
+		facilitator.WithRelayerSigner(&mockRelayer{addr: testRelayer}),
+		facilitator.WithoutGasPolicy(),
+	)
+	resp, err = unbounded.Verify(context.Background(), payload, req)
+	if err != nil || !resp.IsValid {
+		t.Errorf("Expected valid without a gas policy, got %+v (%v)", resp, err)
+	}
+}
@@ -0,0 +1,96 @@
+package multiversx_test
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"x402-integration/mechanisms/multiversx"
+
+	x402 "github.com/coinbase/x402/go"
+	"github.com/coinbase/x402/go/types"
+)
+
+// Alice's devnet test wallet from the MultiversX SDKs signs all test payloads
+const (
+	testSender          = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+	testSenderSecretHex = "413f42575f7f26fad3317a778771212fdb80245850981e48b58a4f25e344e8f9"
+
+	// Bob receives the payments
+	testPayTo = "erd1spyavw0956vq68xj8y4tenjpq2wd5a9p2c6j8gsz7ztyrnpxrruqzu66jx"
+)
+
+// signTransaction fills tx.Signature with the test sender's signature
+func signTransaction(t *testing.T, tx *multiversx.Transaction) {
+	t.Helper()
+	message, err := tx.BytesForSigning()
+	if err != nil {
+		t.Fatalf("Failed to serialize transaction: %v", err)
+	}
+	seed, _ := hex.DecodeString(testSenderSecretHex)
+	tx.Signature = hex.EncodeToString(ed25519.Sign(ed25519.NewKeyFromSeed(seed), message))
+}
+
+// toPaymentPayload converts the relayed payload to the generic x402 payload map
+func toPaymentPayload(rp multiversx.ExactRelayedPayload) types.PaymentPayload {
+	payloadBytes, _ := json.Marshal(rp)
+	var rpMap map[string]interface{}
+	json.Unmarshal(payloadBytes, &rpMap)
+
+	return types.PaymentPayload{
+		Payload: rpMap,
+	}
+}
+
+// writeSimulationSuccess answers /transaction/simulate like the node: success with the
+// hash of the submitted transaction.
+func writeSimulationSuccess(t *testing.T, w http.ResponseWriter, r *http.Request) {
+	t.Helper()
+	var body multiversx.SimulationRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		t.Errorf("Invalid simulation body: %v", err)
+	}
+
+	data, _ := base64.StdEncoding.DecodeString(body.Data)
+	tx := multiversx.Transaction{
+		Nonce:            body.Nonce,
+		Value:            body.Value,
+		Receiver:         body.Receiver,
+		Sender:           body.Sender,
+		GasPrice:         body.GasPrice,
+		GasLimit:         body.GasLimit,
+		Data:             string(data),
+		ChainID:          body.ChainID,
+		Version:          body.Version,
+		Options:          body.Options,
+		Signature:        body.Signature,
+		Relayer:          body.Relayer,
+		RelayerSignature: body.RelayerSignature,
+	}
+	hash, err := tx.Hash()
+	if err != nil {
+		t.Errorf("Failed to hash simulated transaction: %v", err)
+	}
+
+	resp := multiversx.SimulationResponse{}
+	resp.Data.Result.Status = "success"
+	resp.Data.Result.Hash = hash
+	json.NewEncoder(w).Encode(resp)
+}
+
+// assertInvalid checks Verify rejected the payment (not failed) with the given reason
+func assertInvalid(t *testing.T, resp *x402.VerifyResponse, err error, reason string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Expected an invalid payment, got error: %v", err)
+	}
+	if resp.IsValid {
+		t.Fatalf("Expected IsValid=false with reason %s", reason)
+	}
+	if resp.InvalidReason != reason {
+		t.Errorf("Expected invalid reason %s, got %s", reason, resp.InvalidReason)
+	}
+}
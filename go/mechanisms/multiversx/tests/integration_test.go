@@ -5,9 +5,11 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"x402-integration/mechanisms/multiversx"
 	"x402-integration/mechanisms/multiversx/exact/facilitator"
@@ -25,11 +27,7 @@ func TestFacilitatorVerify_EGLD(t *testing.T) {
 		}
 
 		// Return success simulation
-		resp := multiversx.SimulationResponse{}
-		resp.Data.Result.Status = "success"
-		resp.Data.Result.Hash = "mock_hash_123"
-
-		json.NewEncoder(w).Encode(resp)
+		writeSimulationSuccess(t, w, r)
 	}))
 	defer server.Close()
 
@@ -41,11 +39,12 @@ func TestFacilitatorVerify_EGLD(t *testing.T) {
 	rp := multiversx.ExactRelayedPayload{
 		Scheme: multiversx.SchemeExact,
 	}
-	rp.Data.Receiver = "erd1receiver"
-	rp.Data.Sender = "erd1sender"
+	rp.Data.Receiver = testPayTo
+	rp.Data.Sender = testSender
+	rp.Data.ChainID = "D"
 	rp.Data.Value = "100" // Atomic units
 	rp.Data.Nonce = 1
-	rp.Data.Signature = "aabbcc"
+	signTransaction(t, &rp.Data)
 
 	payloadBytes, _ := json.Marshal(rp)
 	var rpMap map[string]interface{}
@@ -57,7 +56,7 @@ func TestFacilitatorVerify_EGLD(t *testing.T) {
 
 	// Create Requirements
 	req := types.PaymentRequirements{
-		PayTo:  "erd1receiver",
+		PayTo:  testPayTo,
 		Amount: "100",
 		Asset:  "EGLD",
 	}
@@ -75,10 +74,7 @@ func TestFacilitatorVerify_EGLD(t *testing.T) {
 
 func TestFacilitatorVerify_ESDT_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := multiversx.SimulationResponse{}
-		resp.Data.Result.Status = "success"
-		resp.Data.Result.Hash = "mock_esdt_hash"
-		json.NewEncoder(w).Encode(resp)
+		writeSimulationSuccess(t, w, r)
 	}))
 	defer server.Close()
 
@@ -104,9 +100,10 @@ func TestFacilitatorVerify_ESDT_Success(t *testing.T) {
 	rp := multiversx.ExactRelayedPayload{}
 	rp.Data.Data = dataString
 	rp.Data.Value = "0"
-	rp.Data.Receiver = "erd1sender" // Self-transfer
-	rp.Data.Sender = "erd1sender"
-	rp.Data.Signature = "dummy_sig"
+	rp.Data.Receiver = testSender // Self-transfer
+	rp.Data.Sender = testSender
+	rp.Data.ChainID = "D"
+	signTransaction(t, &rp.Data)
 
 	payloadBytes, _ := json.Marshal(rp)
 	var rpMap map[string]interface{}
@@ -134,10 +131,7 @@ func TestFacilitatorVerify_ESDT_Success(t *testing.T) {
 func TestFacilitatorVerify_EGLD_Alias_MultiESDT(t *testing.T) {
 	// Verify that EGLD-000000 via MultiESDT payload is accepted
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := multiversx.SimulationResponse{}
-		resp.Data.Result.Status = "success"
-		resp.Data.Result.Hash = "mock_egld_alias_hash"
-		json.NewEncoder(w).Encode(resp)
+		writeSimulationSuccess(t, w, r)
 	}))
 	defer server.Close()
 
@@ -158,9 +152,10 @@ func TestFacilitatorVerify_EGLD_Alias_MultiESDT(t *testing.T) {
 	rp := multiversx.ExactRelayedPayload{}
 	rp.Data.Data = dataString
 	rp.Data.Value = "0"
-	rp.Data.Receiver = "erd1sender"
-	rp.Data.Sender = "erd1sender"
-	rp.Data.Signature = "dummy_sig"
+	rp.Data.Receiver = testSender
+	rp.Data.Sender = testSender
+	rp.Data.ChainID = "D"
+	signTransaction(t, &rp.Data)
 
 	payloadBytes, _ := json.Marshal(rp)
 	var rpMap map[string]interface{}
@@ -184,3 +179,502 @@ func TestFacilitatorVerify_EGLD_Alias_MultiESDT(t *testing.T) {
 		t.Error("IsValid should be true for EGLD-000000 via MultiESDT")
 	}
 }
+
+func TestFacilitatorVerify_LocalSignature(t *testing.T) {
+	simCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		simCalls++
+		writeSimulationSuccess(t, w, r)
+	}))
+	defer server.Close()
+
+	rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+	rp.Data.Receiver = testPayTo
+	rp.Data.Sender = testSender
+	rp.Data.Value = "100"
+	rp.Data.Nonce = 1
+	rp.Data.ChainID = "D"
+	rp.Data.Version = 2
+	signTransaction(t, &rp.Data)
+
+	req := types.PaymentRequirements{
+		PayTo:  testPayTo,
+		Amount: "100",
+		Asset:  "EGLD",
+	}
+
+	// Forged: value raised after signing. Rejected offline.
+	forged := rp
+	forged.Data.Value = "1000"
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+	resp, err := scheme.Verify(context.Background(), toPaymentPayload(forged), req)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonInvalidSignature)
+	if simCalls != 0 {
+		t.Errorf("Forged payload must not reach the gateway, got %d calls", simCalls)
+	}
+
+	// Simulation disabled: valid payload verified without the gateway
+	offline := facilitator.NewExactMultiversXScheme(server.URL, facilitator.WithSimulation(false))
+	resp, err = offline.Verify(context.Background(), toPaymentPayload(rp), req)
+	if err != nil || !resp.IsValid {
+		t.Fatalf("Expected offline verification to pass, got %v", err)
+	}
+	if simCalls != 0 {
+		t.Errorf("Simulation disabled but gateway called %d times", simCalls)
+	}
+}
+
+func TestFacilitatorVerify_SimulationHashMismatch(t *testing.T) {
+	// Gateway simulated something other than what we hold
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := multiversx.SimulationResponse{}
+		resp.Data.Result.Status = "success"
+		resp.Data.Result.Hash = "0000000000000000000000000000000000000000000000000000000000000000"
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+	rp.Data.Receiver = testPayTo
+	rp.Data.Sender = testSender
+	rp.Data.Value = "100"
+	rp.Data.ChainID = "D"
+	rp.Data.Version = 2
+	signTransaction(t, &rp.Data)
+
+	req := types.PaymentRequirements{
+		PayTo:  testPayTo,
+		Amount: "100",
+		Asset:  "EGLD",
+	}
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+	resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), req)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonSimulationFailed)
+}
+
+func TestFacilitatorVerify_ESDT_MultipleTransfers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeSimulationSuccess(t, w, r)
+	}))
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+	payTo, _ := multiversx.AddressFromBech32(testPayTo)
+
+	// Two legs of the requested token add up to the price, plus an unrelated tip
+	data, err := multiversx.TransferData{
+		Function: multiversx.FunctionMultiESDTNFTTransfer,
+		Receiver: payTo,
+		Transfers: []multiversx.TokenTransfer{
+			{Identifier: "USDC-123456", Amount: big.NewInt(60)},
+			{Identifier: "WEGLD-123456", Amount: big.NewInt(5)},
+			{Identifier: "USDC-123456", Amount: big.NewInt(40)},
+		},
+	}.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+	rp.Data.Data = data
+	rp.Data.Value = "0"
+	rp.Data.Receiver = testSender
+	rp.Data.Sender = testSender
+	rp.Data.ChainID = "D"
+	signTransaction(t, &rp.Data)
+
+	req := types.PaymentRequirements{
+		PayTo:  testPayTo,
+		Amount: "100",
+		Asset:  "USDC-123456",
+	}
+
+	resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), req)
+	if err != nil || !resp.IsValid {
+		t.Fatalf("Expected multi-transfer payment to be valid, got %+v (%v)", resp, err)
+	}
+
+	// Malformed hex after the amount is no longer ignored
+	rp.Data.Data = data + "@xyz"
+	signTransaction(t, &rp.Data)
+	resp, err = scheme.Verify(context.Background(), toPaymentPayload(rp), req)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonInvalidPayload)
+}
+
+func TestFacilitatorVerify_ESDT_DirectTransfer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeSimulationSuccess(t, w, r)
+	}))
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+
+	// Wallet-style ESDTTransfer: no destination in the data, the receiver is PayTo
+	data, err := multiversx.TransferData{
+		Function:  multiversx.FunctionESDTTransfer,
+		Transfers: []multiversx.TokenTransfer{{Identifier: "USDC-123456", Amount: big.NewInt(100)}},
+	}.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+	rp.Data.Data = data
+	rp.Data.Value = "0"
+	rp.Data.Receiver = testPayTo
+	rp.Data.Sender = testSender
+	rp.Data.ChainID = "D"
+	signTransaction(t, &rp.Data)
+
+	req := types.PaymentRequirements{
+		PayTo:  testPayTo,
+		Amount: "100",
+		Asset:  "USDC-123456",
+	}
+
+	resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), req)
+	if err != nil || !resp.IsValid {
+		t.Fatalf("Expected direct ESDTTransfer to be valid, got %+v (%v)", resp, err)
+	}
+
+	// Same transfer sent to anyone else is not a payment to PayTo
+	rp.Data.Receiver = "erd1k2s324ww2g0yj38qn2ch2jwctdy8mnfxep94q9arncc6xecg3xaq6mjse8"
+	signTransaction(t, &rp.Data)
+	resp, err = scheme.Verify(context.Background(), toPaymentPayload(rp), req)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonReceiverMismatch)
+}
+
+func TestFacilitatorVerify_NFT(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeSimulationSuccess(t, w, r)
+	}))
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+	payTo, _ := multiversx.AddressFromBech32(testPayTo)
+
+	// Two units of ticket nonce 0x0a
+	req := types.PaymentRequirements{
+		PayTo:  testPayTo,
+		Amount: "2",
+		Asset:  "TICKET-abcdef-0a",
+	}
+
+	tests := []struct {
+		name     string
+		function string
+		transfer multiversx.TokenTransfer
+		reason   string
+	}{
+		{"exact quantity", multiversx.FunctionESDTNFTTransfer, multiversx.TokenTransfer{Identifier: "TICKET-abcdef", Nonce: 10, Amount: big.NewInt(2)}, ""},
+		{"multi transfer", multiversx.FunctionMultiESDTNFTTransfer, multiversx.TokenTransfer{Identifier: "TICKET-abcdef", Nonce: 10, Amount: big.NewInt(2)}, ""},
+		{"wrong nonce", multiversx.FunctionESDTNFTTransfer, multiversx.TokenTransfer{Identifier: "TICKET-abcdef", Nonce: 11, Amount: big.NewInt(2)}, multiversx.InvalidReasonAssetMismatch},
+		{"fungible leg", multiversx.FunctionMultiESDTNFTTransfer, multiversx.TokenTransfer{Identifier: "TICKET-abcdef", Amount: big.NewInt(2)}, multiversx.InvalidReasonAssetMismatch},
+		{"too few", multiversx.FunctionESDTNFTTransfer, multiversx.TokenTransfer{Identifier: "TICKET-abcdef", Nonce: 10, Amount: big.NewInt(1)}, multiversx.InvalidReasonQuantityMismatch},
+		{"too many", multiversx.FunctionESDTNFTTransfer, multiversx.TokenTransfer{Identifier: "TICKET-abcdef", Nonce: 10, Amount: big.NewInt(3)}, multiversx.InvalidReasonQuantityMismatch},
+	}
+
+	for _, tc := range tests {
+		data, err := multiversx.TransferData{
+			Function:  tc.function,
+			Receiver:  payTo,
+			Transfers: []multiversx.TokenTransfer{tc.transfer},
+		}.Encode()
+		if err != nil {
+			t.Fatalf("%s: Encode: %v", tc.name, err)
+		}
+
+		rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+		rp.Data.Data = data
+		rp.Data.Value = "0"
+		rp.Data.Receiver = testSender
+		rp.Data.Sender = testSender
+		rp.Data.ChainID = "D"
+		signTransaction(t, &rp.Data)
+
+		resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), req)
+		if tc.reason == "" {
+			if err != nil || !resp.IsValid {
+				t.Errorf("%s: expected valid, got %+v (%v)", tc.name, resp, err)
+			}
+			continue
+		}
+		assertInvalid(t, resp, err, tc.reason)
+	}
+}
+
+func TestFacilitatorVerify_Bundle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeSimulationSuccess(t, w, r)
+	}))
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+	payTo, _ := multiversx.AddressFromBech32(testPayTo)
+
+	// 100 USDC plus exactly one ticket and at least 1000 EGLD atoms
+	req := types.PaymentRequirements{
+		PayTo:  testPayTo,
+		Amount: "100",
+		Asset:  "USDC-123456",
+		Extra: map[string]interface{}{
+			multiversx.ExtraKeyAssets: []map[string]string{
+				{"asset": "TICKET-abcdef-0a", "amount": "1"},
+				{"asset": "EGLD", "amount": "1000"},
+			},
+		},
+	}
+
+	usdc := multiversx.TokenTransfer{Identifier: "USDC-123456", Amount: big.NewInt(100)}
+	ticket := multiversx.TokenTransfer{Identifier: "TICKET-abcdef", Nonce: 10, Amount: big.NewInt(1)}
+	egld := multiversx.TokenTransfer{Identifier: multiversx.EGLDTokenIdentifier, Amount: big.NewInt(1000)}
+
+	tests := []struct {
+		name      string
+		transfers []multiversx.TokenTransfer
+		reason    string
+	}{
+		{"all legs", []multiversx.TokenTransfer{usdc, ticket, egld}, ""},
+		{"any order, extra EGLD", []multiversx.TokenTransfer{{Identifier: multiversx.EGLDTokenIdentifier, Amount: big.NewInt(5000)}, ticket, usdc}, ""},
+		{"missing leg", []multiversx.TokenTransfer{usdc, egld}, multiversx.InvalidReasonAssetMismatch},
+		{"short EGLD leg", []multiversx.TokenTransfer{usdc, ticket, {Identifier: multiversx.EGLDTokenIdentifier, Amount: big.NewInt(999)}}, multiversx.InvalidReasonInsufficientAmount},
+		{"two tickets", []multiversx.TokenTransfer{usdc, ticket, ticket, egld}, multiversx.InvalidReasonQuantityMismatch},
+	}
+
+	for _, tc := range tests {
+		data, err := multiversx.TransferData{
+			Function:  multiversx.FunctionMultiESDTNFTTransfer,
+			Receiver:  payTo,
+			Transfers: tc.transfers,
+		}.Encode()
+		if err != nil {
+			t.Fatalf("%s: Encode: %v", tc.name, err)
+		}
+
+		rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+		rp.Data.Data = data
+		rp.Data.Value = "0"
+		rp.Data.Receiver = testSender
+		rp.Data.Sender = testSender
+		rp.Data.ChainID = "D"
+		signTransaction(t, &rp.Data)
+
+		resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), req)
+		if tc.reason == "" {
+			if err != nil || !resp.IsValid {
+				t.Errorf("%s: expected valid, got %+v (%v)", tc.name, resp, err)
+			}
+			continue
+		}
+		assertInvalid(t, resp, err, tc.reason)
+	}
+
+	// A malformed asset list is the server's fault, not the payer's
+	req.Extra[multiversx.ExtraKeyAssets] = "USDC-123456"
+	rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+	rp.Data.Value = "0"
+	rp.Data.Receiver = testSender
+	rp.Data.Sender = testSender
+	rp.Data.ChainID = "D"
+	signTransaction(t, &rp.Data)
+	resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), req)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonInvalidRequirements)
+}
+
+func TestFacilitatorVerify_ResourceID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeSimulationSuccess(t, w, r)
+	}))
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+	payTo, _ := multiversx.AddressFromBech32(testPayTo)
+	esdt := func(call string, args ...[]byte) string {
+		data, err := multiversx.TransferData{
+			Function:     multiversx.FunctionMultiESDTNFTTransfer,
+			Receiver:     payTo,
+			Transfers:    []multiversx.TokenTransfer{{Identifier: "USDC-123456", Amount: big.NewInt(100)}},
+			CallFunction: call,
+			CallArgs:     args,
+		}.Encode()
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name     string
+		asset    string
+		receiver string
+		value    string
+		data     string
+		reason   string
+	}{
+		{"EGLD bound", "EGLD", testPayTo, "100", "inv_123", ""},
+		{"EGLD unbound", "EGLD", testPayTo, "100", "", multiversx.InvalidReasonResourceMismatch},
+		{"EGLD other invoice", "EGLD", testPayTo, "100", "inv_124", multiversx.InvalidReasonResourceMismatch},
+		{"ESDT bound", "USDC-123456", testSender, "0", esdt("inv_123"), ""},
+		{"ESDT unbound", "USDC-123456", testSender, "0", esdt(""), multiversx.InvalidReasonResourceMismatch},
+		{"ESDT other invoice", "USDC-123456", testSender, "0", esdt("inv_124"), multiversx.InvalidReasonResourceMismatch},
+		{"ESDT trailing arguments", "USDC-123456", testSender, "0", esdt("inv_123", []byte{1}), multiversx.InvalidReasonResourceMismatch},
+	}
+
+	for _, tc := range tests {
+		rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+		rp.Data.Receiver = tc.receiver
+		rp.Data.Sender = testSender
+		rp.Data.Value = tc.value
+		rp.Data.Data = tc.data
+		rp.Data.ChainID = "D"
+		rp.Data.Version = 1
+		signTransaction(t, &rp.Data)
+
+		req := types.PaymentRequirements{
+			PayTo:   testPayTo,
+			Amount:  "100",
+			Asset:   tc.asset,
+			Network: "multiversx:D",
+			Extra:   map[string]interface{}{multiversx.ExtraKeyResourceID: "inv_123"},
+		}
+		resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), req)
+		if tc.reason == "" {
+			if err != nil || !resp.IsValid {
+				t.Errorf("%s: expected valid, got %+v (%v)", tc.name, resp, err)
+			}
+			continue
+		}
+		assertInvalid(t, resp, err, tc.reason)
+	}
+}
+
+func TestFacilitatorVerify_Expiry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeSimulationSuccess(t, w, r)
+	}))
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+	future := uint64(time.Now().Add(time.Hour).Unix())
+	past := uint64(time.Now().Add(-time.Minute).Unix())
+
+	tests := []struct {
+		name       string
+		validUntil uint64 // in the requirements
+		signed     uint64 // in the transaction data
+		reason     string
+	}{
+		{"not yet expired", future, future, ""},
+		{"expired", past, past, multiversx.InvalidReasonPaymentExpired},
+		{"expiry extended after signing", future, past, multiversx.InvalidReasonResourceMismatch},
+		{"expiry dropped by the client", future, 0, multiversx.InvalidReasonResourceMismatch},
+	}
+
+	for _, tc := range tests {
+		rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+		rp.Data.Receiver = testPayTo
+		rp.Data.Sender = testSender
+		rp.Data.Value = "100"
+		rp.Data.Data = multiversx.PaymentBinding{ResourceID: "inv_123", ValidUntil: tc.signed}.EGLDData()
+		rp.Data.ChainID = "D"
+		rp.Data.Version = 1
+		signTransaction(t, &rp.Data)
+
+		req := types.PaymentRequirements{
+			PayTo:   testPayTo,
+			Amount:  "100",
+			Asset:   "EGLD",
+			Network: "multiversx:D",
+			Extra: map[string]interface{}{
+				multiversx.ExtraKeyResourceID: "inv_123",
+				multiversx.ExtraKeyValidUntil: float64(tc.validUntil), // as decoded from JSON
+			},
+		}
+		resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), req)
+		if tc.reason == "" {
+			if err != nil || !resp.IsValid {
+				t.Errorf("%s: expected valid, got %+v (%v)", tc.name, resp, err)
+			}
+			continue
+		}
+		assertInvalid(t, resp, err, tc.reason)
+	}
+}
+
+func TestFacilitatorVerify_TokenNames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeSimulationSuccess(t, w, r)
+	}))
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+	data, err := multiversx.TransferData{
+		Function:  multiversx.FunctionESDTTransfer,
+		Transfers: []multiversx.TokenTransfer{{Identifier: "USDC-350c4e", Amount: big.NewInt(100)}},
+	}.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+	rp.Data.Receiver = testPayTo
+	rp.Data.Sender = testSender
+	rp.Data.Value = "0"
+	rp.Data.Data = data
+	rp.Data.ChainID = "D"
+	rp.Data.Version = 1
+	signTransaction(t, &rp.Data)
+
+	tests := []struct {
+		asset  string
+		reason string
+	}{
+		{"USDC", ""},        // USDC-350c4e on devnet
+		{"USDC-350c4e", ""}, // the identifier itself
+		{"USDT", multiversx.InvalidReasonInvalidRequirements},        // not on devnet
+		{"USDC-c76f1f", multiversx.InvalidReasonInvalidRequirements}, // mainnet identifier
+		{"WEGLD", multiversx.InvalidReasonInvalidRequirements},
+	}
+	for _, tc := range tests {
+		req := types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: tc.asset, Network: "multiversx:D"}
+		resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), req)
+		if tc.reason == "" {
+			if err != nil || !resp.IsValid {
+				t.Errorf("%s: expected valid, got %+v (%v)", tc.asset, resp, err)
+			}
+			continue
+		}
+		assertInvalid(t, resp, err, tc.reason)
+	}
+
+	// Bundle legs use names as well: EGLD plus 100 USDC (USDC-350c4e on devnet)
+	payTo, _ := multiversx.AddressFromBech32(testPayTo)
+	data, err = multiversx.TransferData{
+		Function: multiversx.FunctionMultiESDTNFTTransfer,
+		Receiver: payTo,
+		Transfers: []multiversx.TokenTransfer{
+			{Identifier: multiversx.EGLDTokenIdentifier, Amount: big.NewInt(1000)},
+			{Identifier: "USDC-350c4e", Amount: big.NewInt(100)},
+		},
+	}.Encode()
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	rp.Data.Receiver = testSender
+	rp.Data.Data = data
+	signTransaction(t, &rp.Data)
+
+	for _, tc := range tests {
+		req := types.PaymentRequirements{PayTo: testPayTo, Amount: "1000", Asset: "EGLD", Network: "multiversx:D", Extra: map[string]interface{}{
+			multiversx.ExtraKeyAssets: []map[string]string{{"asset": tc.asset, "amount": "100"}},
+		}}
+		resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), req)
+		if tc.reason == "" {
+			if err != nil || !resp.IsValid {
+				t.Errorf("%s: expected valid, got %+v (%v)", tc.asset, resp, err)
+			}
+			continue
+		}
+		assertInvalid(t, resp, err, tc.reason)
+	}
+}
@@ -0,0 +1,178 @@
+package multiversx_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"x402-integration/mechanisms/multiversx"
+	"x402-integration/mechanisms/multiversx/exact/facilitator"
+
+	"github.com/coinbase/x402/go/types"
+)
+
+// newLedgerGatewayStub serves devnet config, simulation, send and a settable account nonce
+func newLedgerGatewayStub(t *testing.T, accountNonce *uint64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/network/config":
+			w.Write([]byte(`{"data":{"config":{"erd_chain_id":"D","erd_min_gas_limit":50000,"erd_gas_per_data_byte":1500,"erd_min_gas_price":1000000000,"erd_gas_price_modifier":"0.01"}},"code":"successful"}`))
+		case "/transaction/simulate":
+			writeSimulationSuccess(t, w, r)
+		case "/transaction/send":
+			resp := multiversx.SendTransactionResponse{}
+			resp.Data.TxHash = "ledger_hash"
+			json.NewEncoder(w).Encode(resp)
+		case "/address/" + testSender + "/nonce":
+			fmt.Fprintf(w, `{"data":{"nonce":%d},"code":"successful"}`, atomic.LoadUint64(accountNonce))
+		}
+	}))
+}
+
+func TestFacilitatorLedger_Replay(t *testing.T) {
+	var accountNonce uint64 = 7
+	server := newLedgerGatewayStub(t, &accountNonce)
+	defer server.Close()
+
+	ledger := multiversx.NewMemoryLedger()
+	scheme := facilitator.NewExactMultiversXScheme(server.URL, facilitator.WithPaymentLedger(ledger), facilitator.WithChainID("D"))
+
+	rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+	rp.Data.Receiver = testPayTo
+	rp.Data.Sender = testSender
+	rp.Data.Value = "100"
+	rp.Data.Data = "inv_1"
+	rp.Data.Nonce = 7
+	rp.Data.ChainID = "D"
+	rp.Data.Version = 1
+	signTransaction(t, &rp.Data)
+	payload := toPaymentPayload(rp)
+
+	forResource := func(id string) types.PaymentRequirements {
+		return types.PaymentRequirements{
+			PayTo:   testPayTo,
+			Amount:  "100",
+			Asset:   "EGLD",
+			Network: "multiversx:D",
+			Extra:   map[string]interface{}{"resourceId": id},
+		}
+	}
+
+	// Verify, then settle, the payment for its resource
+	resp, err := scheme.Verify(context.Background(), payload, forResource("inv_1"))
+	if err != nil || !resp.IsValid {
+		t.Fatalf("Expected valid, got %+v (%v)", resp, err)
+	}
+
+	// The same signed payload presented for another resource: caught by the binding when the
+	// requirements carry a resourceId, by the ledger when they do not
+	resp, err = scheme.Verify(context.Background(), payload, forResource("inv_2"))
+	assertInvalid(t, resp, err, multiversx.InvalidReasonResourceMismatch)
+	resp, err = scheme.Verify(context.Background(), payload, forResource(""))
+	assertInvalid(t, resp, err, multiversx.InvalidReasonPaymentReused)
+
+	settleResp, err := scheme.Settle(context.Background(), payload, forResource("inv_1"))
+	if err != nil || !settleResp.Success {
+		t.Fatalf("Settle failed: %+v (%v)", settleResp, err)
+	}
+	// The entry carries the broadcast hash (a relayer co-signature changes it)
+	if entries := ledger.Entries(); len(entries) != 1 || entries[0].TxHash != "ledger_hash" || !entries[0].Settled {
+		t.Errorf("Expected the settled entry under the broadcast hash, got %+v", entries)
+	}
+
+	// Settled: neither verified nor settled again, even for the same resource
+	resp, err = scheme.Verify(context.Background(), payload, forResource("inv_1"))
+	assertInvalid(t, resp, err, multiversx.InvalidReasonPaymentReused)
+	settleResp, err = scheme.Settle(context.Background(), payload, forResource("inv_1"))
+	if err != nil || settleResp.Success || multiversx.ReasonCode(settleResp.ErrorReason) != multiversx.SettleReasonVerificationFailed {
+		t.Errorf("Expected a second settlement to fail verification, got %+v (%v)", settleResp, err)
+	}
+
+	// Entries expire once the chain nonce passed them
+	if err := scheme.PruneLedger(context.Background()); err != nil {
+		t.Fatalf("PruneLedger failed: %v", err)
+	}
+	if len(ledger.Entries()) != 1 {
+		t.Fatalf("Nonce 7 not executed yet, entry must stay: %+v", ledger.Entries())
+	}
+	atomic.StoreUint64(&accountNonce, 8)
+	if err := scheme.PruneLedger(context.Background()); err != nil {
+		t.Fatalf("PruneLedger failed: %v", err)
+	}
+	if len(ledger.Entries()) != 0 {
+		t.Errorf("Expected the executed nonce to be pruned, got %+v", ledger.Entries())
+	}
+
+	// Pruning needs to know which chain the gateway serves
+	unknown := facilitator.NewExactMultiversXScheme(server.URL, facilitator.WithPaymentLedger(ledger))
+	if err := unknown.PruneLedger(context.Background()); err == nil {
+		t.Error("Expected PruneLedger to require the gateway chain ID")
+	}
+}
+
+func TestFacilitatorLedger_PrunedReplayWithoutSimulation(t *testing.T) {
+	var accountNonce uint64 = 7
+	server := newLedgerGatewayStub(t, &accountNonce)
+	defer server.Close()
+
+	ledger := multiversx.NewMemoryLedger()
+	scheme := facilitator.NewExactMultiversXScheme(server.URL,
+		facilitator.WithPaymentLedger(ledger), facilitator.WithChainID("D"), facilitator.WithSimulation(false))
+
+	rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+	rp.Data.Receiver = testPayTo
+	rp.Data.Sender = testSender
+	rp.Data.Value = "100"
+	rp.Data.Nonce = 7
+	rp.Data.ChainID = "D"
+	rp.Data.Version = 1
+	signTransaction(t, &rp.Data)
+	payload := toPaymentPayload(rp)
+	req := types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD", Network: "multiversx:D"}
+
+	settleResp, err := scheme.Settle(context.Background(), payload, req)
+	if err != nil || !settleResp.Success {
+		t.Fatalf("Settle failed: %+v (%v)", settleResp, err)
+	}
+
+	// The transaction executed and its entry is pruned: the account nonce still rejects it
+	atomic.StoreUint64(&accountNonce, 8)
+	if err := scheme.PruneLedger(context.Background()); err != nil {
+		t.Fatalf("PruneLedger failed: %v", err)
+	}
+	if len(ledger.Entries()) != 0 {
+		t.Fatalf("Expected the executed nonce to be pruned, got %+v", ledger.Entries())
+	}
+	resp, err := scheme.Verify(context.Background(), payload, req)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonNonceStale)
+}
+
+// settleFailingLedger accepts payments but cannot store their settlement
+type settleFailingLedger struct {
+	*multiversx.MemoryLedger
+}
+
+func (l settleFailingLedger) MarkSettled(chainID string, sender string, nonce uint64, txHash string) error {
+	return errors.New("disk full")
+}
+
+func TestFacilitatorLedger_MarkSettledFailure(t *testing.T) {
+	var accountNonce uint64 = 7
+	server := newLedgerGatewayStub(t, &accountNonce)
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL,
+		facilitator.WithPaymentLedger(settleFailingLedger{multiversx.NewMemoryLedger()}), facilitator.WithChainID("D"))
+	payload, req := egldSettlementFixture(t)
+
+	// The transaction is broadcast: its hash must reach the caller
+	resp, err := scheme.Settle(context.Background(), payload, req)
+	if err != nil || !resp.Success || resp.Transaction != "ledger_hash" {
+		t.Errorf("Expected a successful settlement despite the ledger failure, got %+v (%v)", resp, err)
+	}
+}
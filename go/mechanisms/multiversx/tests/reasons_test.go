@@ -0,0 +1,240 @@
+package multiversx_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"x402-integration/mechanisms/multiversx"
+	"x402-integration/mechanisms/multiversx/exact/facilitator"
+
+	"github.com/coinbase/x402/go/types"
+)
+
+func TestFacilitatorVerify_InvalidReasons(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeSimulationSuccess(t, w, r)
+	}))
+	defer server.Close()
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+
+	carol := "erd1k2s324ww2g0yj38qn2ch2jwctdy8mnfxep94q9arncc6xecg3xaq6mjse8"
+	payToAddr, _ := multiversx.AddressFromBech32(testPayTo)
+	esdtData := func(token string, amountHex string) string {
+		return fmt.Sprintf("MultiESDTNFTTransfer@%s@01@%s@00@%s", payToAddr.Hex(), hex.EncodeToString([]byte(token)), amountHex)
+	}
+
+	tests := []struct {
+		name   string
+		tx     multiversx.Transaction
+		req    types.PaymentRequirements
+		reason string
+	}{
+		{
+			name:   "EGLD receiver mismatch",
+			tx:     multiversx.Transaction{Receiver: carol, Value: "100", ChainID: "D", Version: 1},
+			req:    types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD", Network: "multiversx:D"},
+			reason: multiversx.InvalidReasonReceiverMismatch,
+		},
+		{
+			name:   "EGLD amount too low",
+			tx:     multiversx.Transaction{Receiver: testPayTo, Value: "99", ChainID: "D", Version: 1},
+			req:    types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD", Network: "multiversx:D"},
+			reason: multiversx.InvalidReasonInsufficientAmount,
+		},
+		{
+			name:   "chain mismatch",
+			tx:     multiversx.Transaction{Receiver: testPayTo, Value: "100", ChainID: "T", Version: 1},
+			req:    types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD", Network: "multiversx:D"},
+			reason: multiversx.InvalidReasonChainMismatch,
+		},
+		{
+			name:   "ESDT asset mismatch",
+			tx:     multiversx.Transaction{Receiver: testSender, Value: "0", Data: esdtData("FAKE-123456", "64"), ChainID: "D", Version: 1},
+			req:    types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "USDC-123456", Network: "multiversx:D"},
+			reason: multiversx.InvalidReasonAssetMismatch,
+		},
+		{
+			name:   "ESDT amount too low",
+			tx:     multiversx.Transaction{Receiver: testSender, Value: "0", Data: esdtData("USDC-123456", "63"), ChainID: "D", Version: 1},
+			req:    types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "USDC-123456", Network: "multiversx:D"},
+			reason: multiversx.InvalidReasonInsufficientAmount,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact, Data: tc.tx}
+			rp.Data.Sender = testSender
+			signTransaction(t, &rp.Data)
+
+			resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), tc.req)
+			assertInvalid(t, resp, err, tc.reason)
+			if resp.Payer != testSender {
+				t.Errorf("Expected payer %s, got %s", testSender, resp.Payer)
+			}
+		})
+	}
+
+	// Undecodable payload
+	resp, err := scheme.Verify(context.Background(), types.PaymentPayload{Payload: map[string]interface{}{"data": "oops"}}, tests[0].req)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonInvalidPayload)
+}
+
+func TestFacilitatorVerify_SimulationOutcomes(t *testing.T) {
+	rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+	rp.Data.Receiver = testPayTo
+	rp.Data.Sender = testSender
+	rp.Data.Value = "100"
+	rp.Data.ChainID = "D"
+	rp.Data.Version = 1
+	signTransaction(t, &rp.Data)
+	req := types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD", Network: "multiversx:D"}
+
+	// Gateway refuses the transaction: a bad payment
+	stale := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "transaction generation failed: lowerNonceInTx: lower nonce in transaction",
+			"code":  "bad_request",
+		})
+	}))
+	defer stale.Close()
+	resp, err := facilitator.NewExactMultiversXScheme(stale.URL).Verify(context.Background(), toPaymentPayload(rp), req)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonNonceStale)
+
+	// Gateway down: an infrastructure failure, not a verdict on the payment
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	if _, err := facilitator.NewExactMultiversXScheme(down.URL).Verify(context.Background(), toPaymentPayload(rp), req); err == nil {
+		t.Error("Expected an error when the gateway is unavailable")
+	}
+
+	unreachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unreachable.Close()
+	if _, err := facilitator.NewExactMultiversXScheme(unreachable.URL).Verify(context.Background(), toPaymentPayload(rp), req); err == nil {
+		t.Error("Expected an error when the gateway is unreachable")
+	}
+}
+
+func TestFacilitatorVerify_StructuralChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Malformed transactions must be rejected before reaching the gateway (%s)", r.URL.Path)
+	}))
+	defer server.Close()
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+
+	carol := "erd1k2s324ww2g0yj38qn2ch2jwctdy8mnfxep94q9arncc6xecg3xaq6mjse8"
+	payToAddr, _ := multiversx.AddressFromBech32(testPayTo)
+	esdtData := fmt.Sprintf("MultiESDTNFTTransfer@%s@01@%s@00@64", payToAddr.Hex(), hex.EncodeToString([]byte("USDC-123456")))
+	egldReq := types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD", Network: "multiversx:D"}
+	esdtReq := types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "USDC-123456", Network: "multiversx:D"}
+
+	tests := []struct {
+		name   string
+		tx     multiversx.Transaction
+		req    types.PaymentRequirements
+		reason string
+	}{
+		{
+			name:   "placeholder sender",
+			tx:     multiversx.Transaction{Sender: "erd1sender", Receiver: testPayTo, Value: "100", ChainID: "D", Version: 1},
+			req:    egldReq,
+			reason: multiversx.InvalidReasonInvalidPayload,
+		},
+		{
+			name:   "mixed-case sender",
+			tx:     multiversx.Transaction{Sender: "erd1QYU5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th", Receiver: testPayTo, Value: "100", ChainID: "D", Version: 1},
+			req:    egldReq,
+			reason: multiversx.InvalidReasonInvalidPayload,
+		},
+		{
+			name:   "malformed receiver",
+			tx:     multiversx.Transaction{Receiver: "erd1receiver", Value: "100", ChainID: "D", Version: 1},
+			req:    egldReq,
+			reason: multiversx.InvalidReasonInvalidPayload,
+		},
+		{
+			name:   "signed value",
+			tx:     multiversx.Transaction{Receiver: testPayTo, Value: "+100", ChainID: "D", Version: 1},
+			req:    egldReq,
+			reason: multiversx.InvalidReasonInvalidPayload,
+		},
+		{
+			name:   "empty chain ID",
+			tx:     multiversx.Transaction{Receiver: testPayTo, Value: "100", Version: 1},
+			req:    types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD"},
+			reason: multiversx.InvalidReasonInvalidPayload,
+		},
+		{
+			name:   "ESDT with EGLD value",
+			tx:     multiversx.Transaction{Receiver: testSender, Value: "1", Data: esdtData, ChainID: "D", Version: 1},
+			req:    esdtReq,
+			reason: multiversx.InvalidReasonInvalidPayload,
+		},
+		{
+			name:   "ESDT multi-transfer not to self",
+			tx:     multiversx.Transaction{Receiver: carol, Value: "0", Data: esdtData, ChainID: "D", Version: 1},
+			req:    esdtReq,
+			reason: multiversx.InvalidReasonReceiverMismatch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact, Data: tc.tx}
+			if rp.Data.Sender == "" {
+				rp.Data.Sender = testSender
+			}
+			signTransaction(t, &rp.Data)
+
+			resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), tc.req)
+			assertInvalid(t, resp, err, tc.reason)
+		})
+	}
+}
+
+func TestFacilitatorVerify_CustomHRP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeSimulationSuccess(t, w, r)
+	}))
+	defer server.Close()
+	scheme := facilitator.NewExactMultiversXScheme(server.URL, facilitator.WithChainID("L"))
+
+	// A local chain whose addresses start with "tst1"
+	reencode := func(bech string) string {
+		addr, _ := multiversx.AddressFromBech32(bech)
+		custom, _ := multiversx.EncodeBech32("tst", addr.PubKey())
+		return custom
+	}
+	sender, payTo := reencode(testSender), reencode(testPayTo)
+	req := types.PaymentRequirements{PayTo: payTo, Amount: "100", Asset: "EGLD", Network: "multiversx:L"}
+
+	for _, tc := range []struct {
+		name   string
+		sender string
+		reason string
+	}{
+		{"custom HRP", sender, ""},
+		{"mainnet HRP on the custom chain", testSender, multiversx.InvalidReasonInvalidPayload},
+	} {
+		rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+		rp.Data = multiversx.Transaction{Sender: tc.sender, Receiver: payTo, Value: "100", ChainID: "L", Version: 1}
+		signTransaction(t, &rp.Data)
+
+		resp, err := scheme.Verify(context.Background(), toPaymentPayload(rp), req)
+		if tc.reason == "" {
+			if err != nil || !resp.IsValid {
+				t.Errorf("%s: expected valid, got %+v (%v)", tc.name, resp, err)
+			}
+			continue
+		}
+		assertInvalid(t, resp, err, tc.reason)
+	}
+}
@@ -0,0 +1,132 @@
+package multiversx_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"x402-integration/mechanisms/multiversx"
+	"x402-integration/mechanisms/multiversx/exact/facilitator"
+
+	x402 "github.com/coinbase/x402/go"
+	"github.com/coinbase/x402/go/types"
+)
+
+// newChainGatewayStub serves network config for chainID and simulations, counting requests
+func newChainGatewayStub(t *testing.T, chainID string, hits *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		switch r.URL.Path {
+		case "/network/config":
+			w.Write([]byte(`{"data":{"config":{"erd_chain_id":"` + chainID + `","erd_min_gas_limit":50000,"erd_gas_per_data_byte":1500,"erd_min_gas_price":1000000000,"erd_gas_price_modifier":"0.01"}},"code":"successful"}`))
+		case "/transaction/simulate":
+			writeSimulationSuccess(t, w, r)
+		}
+	}))
+}
+
+func TestMultiNetworkScheme_Routing(t *testing.T) {
+	var devnetHits, testnetHits int32
+	devnet := newChainGatewayStub(t, "D", &devnetHits)
+	defer devnet.Close()
+	testnet := newChainGatewayStub(t, "T", &testnetHits)
+	defer testnet.Close()
+
+	policy := multiversx.DefaultGasPolicy()
+	scheme, err := facilitator.NewMultiNetworkScheme(map[x402.Network]multiversx.NetworkConfig{
+		"multiversx:D": {APIUrl: devnet.URL, Relayer: &mockRelayer{addr: testRelayer}, GasPolicy: &policy},
+		"multiversx:T": {APIUrl: testnet.URL},
+	})
+	if err != nil {
+		t.Fatalf("NewMultiNetworkScheme failed: %v", err)
+	}
+	if err := scheme.CheckGateways(context.Background()); err != nil {
+		t.Fatalf("CheckGateways failed: %v", err)
+	}
+
+	// Supported kinds: one per network, relayer only where configured
+	kinds := scheme.SupportedKinds()
+	if len(kinds) != 2 || kinds[0].Network != "multiversx:D" || kinds[1].Network != "multiversx:T" {
+		t.Fatalf("Unexpected supported kinds: %+v", kinds)
+	}
+	if kinds[0].Extra[multiversx.ExtraKeyRelayer] != testRelayer || kinds[1].Extra != nil {
+		t.Errorf("Relayer must be advertised on devnet only: %+v", kinds)
+	}
+	if signers := scheme.GetSigners("multiversx:T"); len(signers) != 0 {
+		t.Errorf("Testnet has no relayer, got signers %v", signers)
+	}
+
+	// Each payment reaches the gateway of its own network (aliases included)
+	atomic.StoreInt32(&devnetHits, 0)
+	atomic.StoreInt32(&testnetHits, 0)
+	for _, network := range []string{"multiversx:T", "testnet"} {
+		req := types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD", Network: network}
+		resp, err := scheme.Verify(context.Background(), chainFixture(t, "T"), req)
+		if err != nil || !resp.IsValid {
+			t.Fatalf("%s: expected valid, got %+v (%v)", network, resp, err)
+		}
+	}
+	if devnetHits != 0 || testnetHits != 2 {
+		t.Errorf("Testnet payments must only reach the testnet gateway (devnet %d, testnet %d)", devnetHits, testnetHits)
+	}
+
+	// Gas policy of the devnet relayer applies to devnet payments
+	payload, req := relayedGasFixture(t, 1, 600000000, 1000000000, testRelayer)
+	resp, err := scheme.Verify(context.Background(), payload, req)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonGasPolicy)
+
+	// Networks nobody registered
+	mainnet := types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD", Network: "multiversx:1"}
+	resp, err = scheme.Verify(context.Background(), chainFixture(t, "1"), mainnet)
+	assertInvalid(t, resp, err, multiversx.InvalidReasonUnsupportedNetwork)
+	settleResp, err := scheme.Settle(context.Background(), chainFixture(t, "1"), mainnet)
+	if err != nil || settleResp.Success || multiversx.ReasonCode(settleResp.ErrorReason) != multiversx.SettleReasonUnsupportedNetwork {
+		t.Errorf("Expected unsupported_network settlement, got %+v (%v)", settleResp, err)
+	}
+}
+
+func TestMultiNetworkScheme_Register(t *testing.T) {
+	var hits int32
+	devnet := newChainGatewayStub(t, "D", &hits)
+	defer devnet.Close()
+
+	scheme, err := facilitator.NewMultiNetworkScheme(nil)
+	if err != nil {
+		t.Fatalf("NewMultiNetworkScheme failed: %v", err)
+	}
+
+	// Public gateways are the default for the well-known chains
+	if err := scheme.Register("multiversx:1", multiversx.NetworkConfig{}); err != nil {
+		t.Errorf("Mainnet should default to the public gateway: %v", err)
+	}
+
+	invalid := []struct {
+		network x402.Network
+		config  multiversx.NetworkConfig
+	}{
+		{"eip155:1", multiversx.NetworkConfig{APIUrl: devnet.URL}},
+		{"devnet", multiversx.NetworkConfig{APIUrl: devnet.URL}},                     // not CAIP-2
+		{"multiversx:local", multiversx.NetworkConfig{}},                             // custom chain without URL
+		{"multiversx:D", multiversx.NetworkConfig{APIUrl: devnet.URL, ChainID: "T"}}, // conflicting chain ID
+	}
+	for _, tc := range invalid {
+		if err := scheme.Register(tc.network, tc.config); err == nil {
+			t.Errorf("Expected Register(%s, %+v) to fail", tc.network, tc.config)
+		}
+	}
+
+	// A custom chain whose gateway actually serves devnet is caught at startup
+	if err := scheme.Register("multiversx:local", multiversx.NetworkConfig{APIUrl: devnet.URL}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	scheme, _ = facilitator.NewMultiNetworkScheme(map[x402.Network]multiversx.NetworkConfig{
+		"multiversx:local": {APIUrl: devnet.URL},
+	})
+	err = scheme.CheckGateways(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "multiversx:local") {
+		t.Errorf("Expected CheckGateways to report multiversx:local, got %v", err)
+	}
+}
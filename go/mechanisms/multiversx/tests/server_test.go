@@ -0,0 +1,352 @@
+package multiversx_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"x402-integration/mechanisms/multiversx"
+	"x402-integration/mechanisms/multiversx/exact/facilitator"
+	"x402-integration/mechanisms/multiversx/exact/server"
+
+	x402 "github.com/coinbase/x402/go"
+	"github.com/coinbase/x402/go/types"
+)
+
+func resourceIDOf(req types.PaymentRequirements) string {
+	id, _ := req.Extra[multiversx.ExtraKeyResourceID].(string)
+	return id
+}
+
+func TestServerEnhance_GasHints(t *testing.T) {
+	srv := server.NewExactMultiversXScheme()
+
+	tests := []struct {
+		name     string
+		asset    string
+		kind     types.SupportedKind
+		expected func(req types.PaymentRequirements) uint64
+	}{
+		{
+			name:  "EGLD",
+			asset: "EGLD",
+			// The generated resourceId is the data field
+			expected: func(req types.PaymentRequirements) uint64 { return uint64(50000 + 1500*len(resourceIDOf(req))) },
+		},
+		{
+			name:  "EGLD relayed",
+			asset: "EGLD",
+			kind: types.SupportedKind{
+				Extra: map[string]interface{}{multiversx.ExtraKeyRelayer: testSender},
+			},
+			expected: func(req types.PaymentRequirements) uint64 {
+				return uint64(50000+1500*len(resourceIDOf(req))) + multiversx.RelayerGasOverhead
+			},
+		},
+		{
+			name:  "ESDT",
+			asset: "USDC-123456",
+			expected: func(req types.PaymentRequirements) uint64 {
+				tx, err := multiversx.NewPaymentTransaction(req, testSender, multiversx.TransferFormatMultiESDT)
+				if err != nil {
+					t.Fatalf("NewPaymentTransaction: %v", err)
+				}
+				return uint64(50000 + 1500*len(tx.Data) + 200000 + 800000)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		req := types.PaymentRequirements{
+			PayTo:   testPayTo,
+			Amount:  "100",
+			Asset:   tc.asset,
+			Network: "multiversx:D",
+		}
+		enhanced, err := srv.EnhancePaymentRequirements(context.Background(), req, tc.kind, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		// Hints survive the JSON trip to the client
+		raw, _ := json.Marshal(enhanced.Extra)
+		var extra map[string]interface{}
+		json.Unmarshal(raw, &extra)
+
+		gasLimit, _ := extra[multiversx.ExtraKeyGasLimit].(float64)
+		if uint64(gasLimit) != tc.expected(enhanced) {
+			t.Errorf("%s: expected gas hint %d, got %v", tc.name, tc.expected(enhanced), extra[multiversx.ExtraKeyGasLimit])
+		}
+		if extra[multiversx.ExtraKeyGasPrice] != float64(multiversx.DefaultMinGasPrice) {
+			t.Errorf("%s: expected gas price hint, got %v", tc.name, extra[multiversx.ExtraKeyGasPrice])
+		}
+	}
+}
+
+func TestServerEnhance_ResourceID(t *testing.T) {
+	srv := server.NewExactMultiversXScheme()
+	req := types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD", Network: "multiversx:D"}
+
+	// Generated IDs are unguessable and differ per call
+	first, err := srv.EnhancePaymentRequirements(context.Background(), req, types.SupportedKind{}, nil)
+	if err != nil {
+		t.Fatalf("Enhance failed: %v", err)
+	}
+	second, _ := srv.EnhancePaymentRequirements(context.Background(), req, types.SupportedKind{}, nil)
+	if len(resourceIDOf(first)) != 32 || resourceIDOf(first) == resourceIDOf(second) {
+		t.Errorf("Expected distinct 128-bit resource IDs, got %q and %q", resourceIDOf(first), resourceIDOf(second))
+	}
+
+	// The merchant's own ID is kept, but must be safe to put in the data field
+	req.Extra = map[string]interface{}{multiversx.ExtraKeyResourceID: "invoice-123"}
+	enhanced, err := srv.EnhancePaymentRequirements(context.Background(), req, types.SupportedKind{}, nil)
+	if err != nil || resourceIDOf(enhanced) != "invoice-123" {
+		t.Errorf("Expected merchant resourceId to be kept, got %q (%v)", resourceIDOf(enhanced), err)
+	}
+	req.Extra = map[string]interface{}{multiversx.ExtraKeyResourceID: "ESDTTransfer@4142"}
+	if _, err := srv.EnhancePaymentRequirements(context.Background(), req, types.SupportedKind{}, nil); err == nil {
+		t.Error("Expected an error for a resourceId that looks like a function call")
+	}
+}
+
+func TestServerEnhance_Expiry(t *testing.T) {
+	srv := server.NewExactMultiversXScheme()
+	req := types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD", Network: "multiversx:D", MaxTimeoutSeconds: 300}
+
+	before := time.Now().Unix()
+	enhanced, err := srv.EnhancePaymentRequirements(context.Background(), req, types.SupportedKind{}, nil)
+	if err != nil {
+		t.Fatalf("Enhance failed: %v", err)
+	}
+
+	// Requirements travel as JSON: read them back the way a client does
+	raw, _ := json.Marshal(enhanced)
+	var decoded types.PaymentRequirements
+	json.Unmarshal(raw, &decoded)
+	issuedAt, _ := decoded.Extra[multiversx.ExtraKeyIssuedAt].(float64)
+	binding, err := multiversx.RequiredBinding(decoded)
+	if err != nil {
+		t.Fatalf("RequiredBinding: %v", err)
+	}
+	if int64(issuedAt) < before || binding.ValidUntil != uint64(issuedAt)+300 {
+		t.Errorf("Expected validUntil = issuedAt + 300, got issuedAt %v, validUntil %d", issuedAt, binding.ValidUntil)
+	}
+	if binding.ResourceID != resourceIDOf(enhanced) {
+		t.Errorf("Expiry must be bound to the resourceId, got %+v", binding)
+	}
+
+	// A merchant-set expiry is kept, no timeout means no expiry
+	req.Extra = map[string]interface{}{multiversx.ExtraKeyValidUntil: int64(1700000000)}
+	enhanced, _ = srv.EnhancePaymentRequirements(context.Background(), req, types.SupportedKind{}, nil)
+	if enhanced.Extra[multiversx.ExtraKeyValidUntil] != int64(1700000000) {
+		t.Errorf("Merchant validUntil overwritten: %v", enhanced.Extra[multiversx.ExtraKeyValidUntil])
+	}
+	req.Extra, req.MaxTimeoutSeconds = nil, 0
+	enhanced, _ = srv.EnhancePaymentRequirements(context.Background(), req, types.SupportedKind{}, nil)
+	if _, ok := enhanced.Extra[multiversx.ExtraKeyValidUntil]; ok {
+		t.Errorf("Unexpected validUntil without MaxTimeoutSeconds: %v", enhanced.Extra)
+	}
+}
+
+func TestServerParsePrice(t *testing.T) {
+	srv := server.NewExactMultiversXScheme(server.WithTokenDecimals("MYTOKEN-abcdef", 2))
+
+	valid := []struct {
+		name     string
+		price    x402.Price
+		asset    string
+		expected string
+	}{
+		{"EGLD string", "0.05 EGLD", "EGLD", "50000000000000000"},
+		{"bare number string", "0.05", "EGLD", "50000000000000000"},
+		{"dollars", "$0.01", "USDC-c76f1f", "10000"},
+		{"token string", "1.5 USDC-c76f1f", "USDC-c76f1f", "1500000"},
+		{"configured token", "2.5 MYTOKEN-abcdef", "MYTOKEN-abcdef", "250"},
+		{"number", 0.25, "EGLD", "250000000000000000"},
+		{"map with number", map[string]interface{}{"amount": 0.25, "asset": "USDC-c76f1f"}, "USDC-c76f1f", "250000"},
+		{"map with atomic string", map[string]interface{}{"amount": "1000", "asset": "USDC-c76f1f"}, "USDC-c76f1f", "1000"},
+		{"asset amount", x402.AssetAmount{Amount: "1000"}, "EGLD", "1000"},
+	}
+	for _, tc := range valid {
+		got, err := srv.ParsePrice(tc.price, "multiversx:1")
+		if err != nil || got.Asset != tc.asset || got.Amount != tc.expected {
+			t.Errorf("%s: got %+v (%v), expected %s %s", tc.name, got, err, tc.expected, tc.asset)
+		}
+	}
+
+	invalid := []struct {
+		name  string
+		price x402.Price
+	}{
+		{"precision loss", "0.0000001 USDC-c76f1f"},
+		{"precision loss in dollars", "$0.0000001"},
+		{"zero", "0 EGLD"},
+		{"negative", "-1 EGLD"},
+		{"negative number", -0.5},
+		{"zero map amount", map[string]interface{}{"amount": 0.0, "asset": "USDC-c76f1f"}},
+		{"zero atomic amount", x402.AssetAmount{Amount: "0"}},
+		{"missing map amount", map[string]interface{}{"asset": "EGLD"}},
+		{"unknown decimals", "1 OTHER-123456"},
+		{"garbage", "one EGLD"},
+		{"too many fields", "1 EGLD please"},
+		{"unsupported type", []string{"1"}},
+	}
+	for _, tc := range invalid {
+		got, err := srv.ParsePrice(tc.price, "multiversx:1")
+		if err == nil {
+			t.Errorf("%s: expected an error, got %+v", tc.name, got)
+		}
+		if !reflect.DeepEqual(got, x402.AssetAmount{}) {
+			t.Errorf("%s: expected no amount alongside the error, got %+v", tc.name, got)
+		}
+	}
+
+	// A token the registry refuses is reported, not dropped
+	srv = server.NewExactMultiversXScheme(server.WithTokenDecimals("MYTOKEN-abcdef", 19))
+	if srv.Err() == nil {
+		t.Error("Expected an error for 19 decimals")
+	}
+	if _, err := srv.ParsePrice("1 EGLD", "multiversx:1"); err == nil {
+		t.Error("Expected ParsePrice to report the invalid option")
+	}
+
+	// "$" can be paid in another stablecoin
+	srv = server.NewExactMultiversXScheme(server.WithDollarAsset("USDT-f8c08c"))
+	got, err := srv.ParsePrice("$1", "multiversx:1")
+	if err != nil || got.Asset != "USDT-f8c08c" || got.Amount != "1000000" {
+		t.Errorf("Expected 1000000 USDT-f8c08c, got %+v (%v)", got, err)
+	}
+}
+
+func TestServerTokens(t *testing.T) {
+	srv := server.NewExactMultiversXScheme()
+
+	// Names resolve to the identifier of the route's network
+	got, err := srv.ParsePrice("1.5 USDC", "multiversx:D")
+	if err != nil || got.Asset != "USDC-350c4e" || got.Amount != "1500000" {
+		t.Errorf("Expected 1500000 USDC-350c4e, got %+v (%v)", got, err)
+	}
+	if _, err := srv.ParsePrice("$1", "multiversx:T"); err == nil {
+		t.Error("Expected an error: no USDC on testnet")
+	}
+	if _, err := srv.ParsePrice("1 USDC-c76f1f", "multiversx:D"); err == nil {
+		t.Error("Expected an error for a mainnet identifier on devnet")
+	}
+
+	req := types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "usdc", Network: "multiversx:1"}
+	enhanced, err := srv.EnhancePaymentRequirements(context.Background(), req, types.SupportedKind{}, nil)
+	if err != nil || enhanced.Asset != "USDC-c76f1f" {
+		t.Errorf("Expected asset USDC-c76f1f, got %q (%v)", enhanced.Asset, err)
+	}
+	req.Network = "multiversx:T"
+	if _, err := srv.EnhancePaymentRequirements(context.Background(), req, types.SupportedKind{}, nil); err == nil {
+		t.Error("Expected an error for a token missing on the network")
+	}
+
+	// Callers that never passed a network get the default one (devnet)
+	if got, err := srv.ParsePrice("$1", ""); err != nil || got.Asset != "USDC-350c4e" {
+		t.Errorf("Expected devnet USDC without a network, got %+v (%v)", got, err)
+	}
+	if _, err := srv.EnhancePaymentRequirements(context.Background(), types.PaymentRequirements{PayTo: testPayTo, Amount: "100"}, types.SupportedKind{}, nil); err != nil {
+		t.Errorf("Expected requirements without a network to be accepted: %v", err)
+	}
+
+	// Bundle legs are resolved too
+	req = types.PaymentRequirements{PayTo: testPayTo, Amount: "1000", Asset: "EGLD", Network: "multiversx:D", Extra: map[string]interface{}{
+		multiversx.ExtraKeyAssets: []map[string]string{{"asset": "USDC", "amount": "100"}},
+	}}
+	enhanced, err = srv.EnhancePaymentRequirements(context.Background(), req, types.SupportedKind{}, nil)
+	if err != nil {
+		t.Fatalf("Enhance failed: %v", err)
+	}
+	legs, err := multiversx.RequiredTransfers(enhanced)
+	if err != nil || len(legs) != 2 || legs[1].Identifier != "USDC-350c4e" {
+		t.Errorf("Expected a USDC-350c4e leg, got %+v (%v)", legs, err)
+	}
+
+	// Decimals of other tokens come from the gateway
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"tokenData":{"tokenIdentifier":"ABC-123456","decimals":2}},"code":"successful"}`))
+	}))
+	defer gateway.Close()
+	tokens := multiversx.NewDefaultTokenRegistry()
+	tokens.SetFetcher("D", multiversx.NewGatewayProvider(gateway.URL, nil))
+	srv = server.NewExactMultiversXScheme(server.WithTokenRegistry(tokens))
+	got, err = srv.ParsePrice("2.5 ABC-123456", "multiversx:D")
+	if err != nil || got.Asset != "ABC-123456" || got.Amount != "250" {
+		t.Errorf("Expected 250 ABC-123456, got %+v (%v)", got, err)
+	}
+
+	// WithTokenDecimals does not leak into a shared registry, whatever the option order
+	shared := multiversx.NewDefaultTokenRegistry()
+	for _, opts := range [][]server.Option{
+		{server.WithTokenRegistry(shared), server.WithTokenDecimals("MYTOKEN-abcdef", 2)},
+		{server.WithTokenDecimals("MYTOKEN-abcdef", 2), server.WithTokenRegistry(shared)},
+	} {
+		srv = server.NewExactMultiversXScheme(opts...)
+		if got, err := srv.ParsePrice("1.5 MYTOKEN-abcdef", "multiversx:D"); err != nil || got.Amount != "150" {
+			t.Errorf("Expected 150 MYTOKEN-abcdef, got %+v (%v)", got, err)
+		}
+	}
+	if _, err := shared.Resolve(context.Background(), "D", "MYTOKEN-abcdef"); err == nil {
+		t.Error("WithTokenDecimals must not register into the shared registry")
+	}
+
+	// A nil registry keeps the default one
+	srv = server.NewExactMultiversXScheme(server.WithTokenRegistry(nil))
+	if got, err := srv.ParsePrice("$1", "multiversx:1"); err != nil || got.Asset != "USDC-c76f1f" {
+		t.Errorf("Expected the default registry, got %+v (%v)", got, err)
+	}
+}
+
+func TestServerEnhance_RoundTrip(t *testing.T) {
+	srv := server.NewExactMultiversXScheme()
+	verifier := facilitator.NewExactMultiversXScheme("http://127.0.0.1:0", facilitator.WithSimulation(false))
+	route := types.PaymentRequirements{PayTo: testPayTo, Amount: "100", Asset: "EGLD", Network: "multiversx:D", MaxTimeoutSeconds: 300}
+
+	// pay signs what the client builds from the issued requirements
+	pay := func(req types.PaymentRequirements) types.PaymentPayload {
+		tx, err := multiversx.NewPaymentTransaction(req, testSender, multiversx.TransferFormatMultiESDT)
+		if err != nil {
+			t.Fatalf("Failed to build payment: %v", err)
+		}
+		tx.GasLimit, tx.GasPrice = 100000, 1000000000
+		rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact, Data: tx}
+		signTransaction(t, &rp.Data)
+		return toPaymentPayload(rp)
+	}
+	enhance := func(req types.PaymentRequirements) types.PaymentRequirements {
+		enhanced, err := srv.EnhancePaymentRequirements(context.Background(), req, types.SupportedKind{}, nil)
+		if err != nil {
+			t.Fatalf("Enhance failed: %v", err)
+		}
+		return enhanced
+	}
+
+	// Stored requirements verify the payment made for them
+	issued := enhance(route)
+	payload := pay(issued)
+	resp, err := verifier.Verify(context.Background(), payload, issued)
+	if err != nil || !resp.IsValid {
+		t.Fatalf("Expected the payment to verify against the issued requirements, got %v (%v)", resp, err)
+	}
+
+	// Rebuilt requirements carry a new resourceId: the payment is for another issuance
+	resp, err = verifier.Verify(context.Background(), payload, enhance(route))
+	assertInvalid(t, resp, err, multiversx.InvalidReasonResourceMismatch)
+
+	// Merchant-set binding: rebuilding yields the same requirements
+	route.Extra = map[string]interface{}{
+		multiversx.ExtraKeyResourceID: "order-42",
+		multiversx.ExtraKeyValidUntil: time.Now().Unix() + 300,
+	}
+	payload = pay(enhance(route))
+	resp, err = verifier.Verify(context.Background(), payload, enhance(route))
+	if err != nil || !resp.IsValid {
+		t.Errorf("Expected a merchant-bound payment to verify against rebuilt requirements, got %v (%v)", resp, err)
+	}
+}
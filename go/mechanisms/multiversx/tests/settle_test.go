@@ -0,0 +1,371 @@
+package multiversx_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"x402-integration/mechanisms/multiversx"
+	"x402-integration/mechanisms/multiversx/exact/facilitator"
+
+	"github.com/coinbase/x402/go/types"
+)
+
+// newGatewayStub mocks the simulate and send endpoints of a MultiversX gateway.
+// sendStatus/sendError control how /transaction/send answers.
+func newGatewayStub(t *testing.T, sendStatus int, sendError string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/transaction/simulate":
+			writeSimulationSuccess(t, w, r)
+		case "/transaction/send":
+			var body multiversx.SimulationRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("Invalid send body: %v", err)
+			}
+			if body.Signature == "" {
+				t.Error("Broadcast transaction is missing the signature")
+			}
+
+			w.WriteHeader(sendStatus)
+			resp := multiversx.SendTransactionResponse{}
+			if sendError != "" {
+				resp.Error = sendError
+				resp.Code = "bad_request"
+			} else {
+				resp.Data.TxHash = "a1b2c3d4"
+				resp.Code = "successful"
+			}
+			json.NewEncoder(w).Encode(resp)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func egldSettlementFixture(t *testing.T) (types.PaymentPayload, types.PaymentRequirements) {
+	rp := multiversx.ExactRelayedPayload{
+		Scheme: multiversx.SchemeExact,
+	}
+	rp.Data.Receiver = testPayTo
+	rp.Data.Sender = testSender
+	rp.Data.Value = "100"
+	rp.Data.Nonce = 7
+	rp.Data.ChainID = "D"
+	rp.Data.Version = 1
+	signTransaction(t, &rp.Data)
+
+	return toPaymentPayload(rp), types.PaymentRequirements{
+		PayTo:   testPayTo,
+		Amount:  "100",
+		Asset:   "EGLD",
+		Network: "multiversx:D",
+	}
+}
+
+func TestFacilitatorSettle_Success(t *testing.T) {
+	server := newGatewayStub(t, http.StatusOK, "")
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+	payload, req := egldSettlementFixture(t)
+
+	resp, err := scheme.Settle(context.Background(), payload, req)
+	if err != nil {
+		t.Fatalf("Settle failed: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("Expected success, got reason %s", resp.ErrorReason)
+	}
+	if resp.Transaction != "a1b2c3d4" {
+		t.Errorf("Wrong transaction hash: %s", resp.Transaction)
+	}
+	if resp.Payer != testSender {
+		t.Errorf("Wrong payer: %s", resp.Payer)
+	}
+	if string(resp.Network) != "multiversx:D" {
+		t.Errorf("Wrong network: %s", resp.Network)
+	}
+}
+
+func TestFacilitatorSettle_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		gwError  string
+		expected string
+	}{
+		{"NonceTooLow", "transaction generation failed: lower nonce in transaction", multiversx.SettleReasonNonceTooLow},
+		{"InsufficientFunds", "transaction generation failed: insufficient funds for address erd1sender", multiversx.SettleReasonInsufficientFunds},
+		{"Mempool", "transaction generation failed: invalid transaction", multiversx.SettleReasonTransactionRejected},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newGatewayStub(t, http.StatusBadRequest, tc.gwError)
+			defer server.Close()
+
+			scheme := facilitator.NewExactMultiversXScheme(server.URL)
+			payload, req := egldSettlementFixture(t)
+
+			resp, err := scheme.Settle(context.Background(), payload, req)
+			if err != nil {
+				t.Fatalf("Rejections must not be Go errors: %v", err)
+			}
+			if resp.Success {
+				t.Fatal("Expected Success=false")
+			}
+			if multiversx.ReasonCode(resp.ErrorReason) != tc.expected {
+				t.Errorf("Expected reason %s, got %s", tc.expected, resp.ErrorReason)
+			}
+			if !strings.Contains(resp.ErrorReason, tc.gwError) {
+				t.Errorf("Reason should carry the gateway message, got %s", resp.ErrorReason)
+			}
+			if resp.Transaction != "" {
+				t.Errorf("No transaction hash expected, got %s", resp.Transaction)
+			}
+		})
+	}
+}
+
+func TestFacilitatorSettle_VerificationFailure(t *testing.T) {
+	server := newGatewayStub(t, http.StatusOK, "")
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+	payload, req := egldSettlementFixture(t)
+	req.Amount = "1000" // Payload only carries 100
+
+	resp, err := scheme.Settle(context.Background(), payload, req)
+	if err != nil {
+		t.Fatalf("Settle failed: %v", err)
+	}
+	if resp.Success {
+		t.Fatal("Underpaying payload must not be settled")
+	}
+	if multiversx.ReasonCode(resp.ErrorReason) != multiversx.SettleReasonVerificationFailed {
+		t.Errorf("Wrong reason: %s", resp.ErrorReason)
+	}
+}
+
+func TestFacilitatorSettle_Expired(t *testing.T) {
+	server := newGatewayStub(t, http.StatusOK, "")
+	defer server.Close()
+
+	scheme := facilitator.NewExactMultiversXScheme(server.URL)
+	expired := multiversx.PaymentBinding{ResourceID: "inv_123", ValidUntil: uint64(time.Now().Add(-time.Minute).Unix())}
+
+	rp := multiversx.ExactRelayedPayload{Scheme: multiversx.SchemeExact}
+	rp.Data.Receiver = testPayTo
+	rp.Data.Sender = testSender
+	rp.Data.Value = "100"
+	rp.Data.Data = expired.EGLDData()
+	rp.Data.ChainID = "D"
+	rp.Data.Version = 1
+	signTransaction(t, &rp.Data)
+
+	req := types.PaymentRequirements{
+		PayTo:   testPayTo,
+		Amount:  "100",
+		Asset:   "EGLD",
+		Network: "multiversx:D",
+		Extra: map[string]interface{}{
+			multiversx.ExtraKeyResourceID: expired.ResourceID,
+			multiversx.ExtraKeyValidUntil: expired.ValidUntil,
+		},
+	}
+
+	// A payment signed in time but settled late is never broadcast
+	resp, err := scheme.Settle(context.Background(), toPaymentPayload(rp), req)
+	if err != nil {
+		t.Fatalf("Settle failed: %v", err)
+	}
+	if resp.Success {
+		t.Fatal("Expired payment must not be settled")
+	}
+	if multiversx.ReasonCode(resp.ErrorReason) != multiversx.SettleReasonPaymentExpired {
+		t.Errorf("Wrong reason: %s", resp.ErrorReason)
+	}
+}
+
+// newFinalityGatewayStub serves simulate/send plus status polling.
+// Each status poll advances to the next entry of txStates; the last entry repeats.
+// processed, when set, is what /status reports for each poll instead of the transaction's own status.
+func newFinalityGatewayStub(t *testing.T, txStates []multiversx.TransactionOnNetwork, processed []string) *httptest.Server {
+	var mu sync.Mutex
+	polls := 0
+	current := txStates[0]
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/transaction/simulate":
+			writeSimulationSuccess(t, w, r)
+		case r.URL.Path == "/transaction/send":
+			resp := multiversx.SendTransactionResponse{}
+			resp.Data.TxHash = "a1b2c3d4"
+			json.NewEncoder(w).Encode(resp)
+		case r.URL.Path == "/transaction/a1b2c3d4/status":
+			current = txStates[polls]
+			resp := multiversx.TransactionStatusResponse{}
+			resp.Data.Status = current.Status
+			if processed != nil {
+				resp.Data.Status = processed[polls]
+			}
+			if polls < len(txStates)-1 {
+				polls++
+			}
+			json.NewEncoder(w).Encode(resp)
+		case r.URL.Path == "/transaction/a1b2c3d4":
+			if r.URL.Query().Get("withResults") != "true" {
+				t.Error("Transaction details must be requested withResults=true")
+			}
+			resp := multiversx.TransactionResponse{}
+			resp.Data.Transaction = current
+			json.NewEncoder(w).Encode(resp)
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestFacilitatorSettle_Finality(t *testing.T) {
+	signalError := multiversx.TransactionEvent{
+		Identifier: "signalError",
+		Topics: []string{
+			base64.StdEncoding.EncodeToString([]byte("erd1sender")),
+			base64.StdEncoding.EncodeToString([]byte("insufficient funds for token")),
+		},
+	}
+
+	tests := []struct {
+		name           string
+		states         []multiversx.TransactionOnNetwork
+		processed      []string
+		maxTimeout     int
+		expectSuccess  bool
+		expectedReason string
+		expectedDetail string
+	}{
+		{
+			name:          "IntraShardSuccess",
+			states:        []multiversx.TransactionOnNetwork{{Status: "pending"}, {Status: "success"}},
+			expectSuccess: true,
+		},
+		{
+			name: "CrossShardWaitsForDestination",
+			states: []multiversx.TransactionOnNetwork{
+				{Status: "success", SourceShard: 0, DestinationShard: 1},
+				{Status: "success", SourceShard: 0, DestinationShard: 1, NotarizedAtDestinationInMetaNonce: 42},
+			},
+			expectSuccess: true,
+		},
+		{
+			name: "FailedWithSignalError",
+			states: []multiversx.TransactionOnNetwork{{
+				Status: "fail",
+				Logs:   &multiversx.TransactionLogs{Events: []multiversx.TransactionEvent{signalError}},
+			}},
+			expectedReason: multiversx.SettleReasonTransactionFailed,
+			expectedDetail: "insufficient funds for token",
+		},
+		{
+			name: "FailedWithSCRReturnMessage",
+			states: []multiversx.TransactionOnNetwork{{
+				Status:               "fail",
+				SmartContractResults: []multiversx.SmartContractResult{{ReturnMessage: "new NFT data on sender"}},
+			}},
+			expectedReason: multiversx.SettleReasonTransactionFailed,
+			expectedDetail: "new NFT data on sender",
+		},
+		{
+			name: "SuccessWithSignalError",
+			states: []multiversx.TransactionOnNetwork{{
+				Status: "success",
+				Logs:   &multiversx.TransactionLogs{Events: []multiversx.TransactionEvent{signalError}},
+			}},
+			expectedReason: multiversx.SettleReasonTransactionFailed,
+			expectedDetail: "insufficient funds for token",
+		},
+		{
+			name: "SuccessWithFailedSCR",
+			states: []multiversx.TransactionOnNetwork{{
+				Status: "success",
+				SmartContractResults: []multiversx.SmartContractResult{{
+					Logs: &multiversx.TransactionLogs{Events: []multiversx.TransactionEvent{signalError}},
+				}},
+			}},
+			expectedReason: multiversx.SettleReasonTransactionFailed,
+			expectedDetail: "insufficient funds for token",
+		},
+		{
+			name: "SuccessWithGasRefund",
+			states: []multiversx.TransactionOnNetwork{{
+				Status:               "success",
+				SmartContractResults: []multiversx.SmartContractResult{{ReturnMessage: "gas refund for relayer"}},
+			}},
+			expectSuccess: true,
+		},
+		{
+			name:           "ProcessedFailOverridesSuccess",
+			states:         []multiversx.TransactionOnNetwork{{Status: "success"}},
+			processed:      []string{"fail"},
+			expectedReason: multiversx.SettleReasonTransactionFailed,
+			expectedDetail: "status fail",
+		},
+		{
+			name:          "ProcessedSuccessOverridesPending",
+			states:        []multiversx.TransactionOnNetwork{{Status: "pending"}},
+			processed:     []string{"success"},
+			expectSuccess: true,
+		},
+		{
+			name: "CrossShardNeverExecutedAtDestination",
+			states: []multiversx.TransactionOnNetwork{
+				{Status: "success", SourceShard: 0, DestinationShard: 2},
+			},
+			maxTimeout:     1,
+			expectedReason: multiversx.SettleReasonFinalityTimeout,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newFinalityGatewayStub(t, tc.states, tc.processed)
+			defer server.Close()
+
+			scheme := facilitator.NewExactMultiversXScheme(server.URL,
+				facilitator.WithSettlementMode(facilitator.SettlementModeFinality),
+				facilitator.WithPollInterval(10*time.Millisecond),
+			)
+			payload, req := egldSettlementFixture(t)
+			req.MaxTimeoutSeconds = tc.maxTimeout
+
+			resp, err := scheme.Settle(context.Background(), payload, req)
+			if err != nil {
+				t.Fatalf("Settle failed: %v", err)
+			}
+			if resp.Success != tc.expectSuccess {
+				t.Fatalf("Expected Success=%v, got %v (reason %s)", tc.expectSuccess, resp.Success, resp.ErrorReason)
+			}
+			if resp.Transaction != "a1b2c3d4" {
+				t.Errorf("Transaction hash should always be reported once broadcast, got %q", resp.Transaction)
+			}
+			if tc.expectedReason != "" && multiversx.ReasonCode(resp.ErrorReason) != tc.expectedReason {
+				t.Errorf("Expected reason %s, got %s", tc.expectedReason, resp.ErrorReason)
+			}
+			if tc.expectedDetail != "" && !strings.Contains(resp.ErrorReason, tc.expectedDetail) {
+				t.Errorf("Expected reason to contain %q, got %s", tc.expectedDetail, resp.ErrorReason)
+			}
+		})
+	}
+}
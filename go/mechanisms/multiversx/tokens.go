@@ -0,0 +1,281 @@
+package multiversx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/coinbase/x402/go/types"
+)
+
+// AnyChain registers a token identifier for every chain (EGLD, or tokens added by configuration)
+const AnyChain = "*"
+
+// Token describes a token under a friendly name and its identifier on each chain
+type Token struct {
+	Name        string            // e.g. "USDC", matched case-insensitively
+	Decimals    int               // atomic units per token: 10^Decimals
+	Identifiers map[string]string // chain ID -> identifier, AnyChain for all chains
+}
+
+// TokenInfo is a token resolved on one chain
+type TokenInfo struct {
+	Identifier string
+	Decimals   int
+}
+
+// TokenFetcher is implemented by providers able to look up a token's properties (GET /esdt/{id})
+type TokenFetcher interface {
+	GetToken(ctx context.Context, identifier string) (TokenInfo, error)
+}
+
+// WellKnownTokens seed NewDefaultTokenRegistry
+var WellKnownTokens = []Token{
+	{Name: "EGLD", Decimals: 18, Identifiers: map[string]string{AnyChain: "EGLD"}},
+	{Name: "USDC", Decimals: 6, Identifiers: map[string]string{"1": "USDC-c76f1f", "D": "USDC-350c4e"}},
+	{Name: "USDT", Decimals: 6, Identifiers: map[string]string{"1": "USDT-f8c08c"}},
+	{Name: "WEGLD", Decimals: 18, Identifiers: map[string]string{"1": "WEGLD-bd4d79"}},
+}
+
+// TokenRegistry maps friendly names to per-chain identifiers and decimals.
+// Identifiers it does not know are fetched through the chain's TokenFetcher, if any, and cached.
+type TokenRegistry struct {
+	mu       sync.RWMutex
+	names    map[string]Token            // upper-case name -> token
+	byID     map[string]map[string]Token // identifier -> chain ID (or AnyChain) -> token
+	fetched  map[string]TokenInfo        // chain ID + "/" + identifier -> fetched token
+	fetchers map[string]TokenFetcher     // chain ID -> fetcher
+}
+
+// NewTokenRegistry creates a registry holding only the given tokens
+func NewTokenRegistry(tokens ...Token) (*TokenRegistry, error) {
+	r := &TokenRegistry{
+		names:    make(map[string]Token),
+		byID:     make(map[string]map[string]Token),
+		fetched:  make(map[string]TokenInfo),
+		fetchers: make(map[string]TokenFetcher),
+	}
+	for _, token := range tokens {
+		if err := r.Register(token); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// NewDefaultTokenRegistry creates a registry seeded with WellKnownTokens
+func NewDefaultTokenRegistry() *TokenRegistry {
+	r, err := NewTokenRegistry(WellKnownTokens...)
+	if err != nil {
+		panic(fmt.Sprintf("invalid well-known tokens: %v", err))
+	}
+	return r
+}
+
+// Register adds a token, replacing any token of the same name
+func (r *TokenRegistry) Register(token Token) error {
+	if token.Name == "" || len(token.Identifiers) == 0 {
+		return fmt.Errorf("token needs a name and at least one identifier")
+	}
+	if token.Decimals < 0 || token.Decimals > 18 {
+		return fmt.Errorf("invalid decimals %d for token %s", token.Decimals, token.Name)
+	}
+	for chainID, identifier := range token.Identifiers {
+		if identifier != "EGLD" {
+			if _, err := ParseTokenIdentifier(identifier); err != nil {
+				return fmt.Errorf("token %s on chain %s: %v", token.Name, chainID, err)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := strings.ToUpper(token.Name)
+	if old, ok := r.names[name]; ok {
+		for chainID, identifier := range old.Identifiers {
+			delete(r.byID[identifier], chainID)
+		}
+	}
+	r.names[name] = token
+	for chainID, identifier := range token.Identifiers {
+		if r.byID[identifier] == nil {
+			r.byID[identifier] = make(map[string]Token)
+		}
+		r.byID[identifier][chainID] = token
+	}
+	return nil
+}
+
+// Clone returns a registry holding the same tokens, fetchers and fetched tokens,
+// so tokens registered on either one do not show in the other
+func (r *TokenRegistry) Clone() *TokenRegistry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c := &TokenRegistry{
+		names:    make(map[string]Token, len(r.names)),
+		byID:     make(map[string]map[string]Token, len(r.byID)),
+		fetched:  make(map[string]TokenInfo, len(r.fetched)),
+		fetchers: make(map[string]TokenFetcher, len(r.fetchers)),
+	}
+	for name, token := range r.names {
+		c.names[name] = token
+	}
+	for identifier, chains := range r.byID {
+		c.byID[identifier] = make(map[string]Token, len(chains))
+		for chainID, token := range chains {
+			c.byID[identifier][chainID] = token
+		}
+	}
+	for key, info := range r.fetched {
+		c.fetched[key] = info
+	}
+	for chainID, fetcher := range r.fetchers {
+		c.fetchers[chainID] = fetcher
+	}
+	return c
+}
+
+// SetFetcher sets where tokens unknown to the registry are looked up on chainID
+func (r *TokenRegistry) SetFetcher(chainID string, fetcher TokenFetcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fetchers[chainID] = fetcher
+}
+
+// Identifier resolves asset, a friendly name or an identifier, to its identifier on chainID.
+// Identifiers the registry does not know are returned as they are: amounts in atomic units
+// need no decimals, and the simulation rejects tokens that do not exist.
+func (r *TokenRegistry) Identifier(chainID string, asset string) (string, error) {
+	info, known, err := r.lookup(chainID, asset)
+	if err != nil || known {
+		return info.Identifier, err
+	}
+	if strings.Contains(asset, "-") {
+		if _, err := ParseTokenIdentifier(asset); err != nil {
+			return "", err
+		}
+		return asset, nil
+	}
+	return "", fmt.Errorf("unknown token %s", asset)
+}
+
+// ResolveAssets names every asset of requirements, Asset and the legs of Extra["assets"],
+// by its identifier on chainID. Extra is copied, the caller's map is left untouched.
+func (r *TokenRegistry) ResolveAssets(chainID string, requirements types.PaymentRequirements) (types.PaymentRequirements, error) {
+	if requirements.Asset != "" {
+		identifier, err := r.Identifier(chainID, requirements.Asset)
+		if err != nil {
+			return requirements, err
+		}
+		requirements.Asset = identifier
+	}
+
+	raw, ok := requirements.Extra[ExtraKeyAssets]
+	if !ok || raw == nil {
+		return requirements, nil
+	}
+	// Round-trip through JSON like RequiredTransfers: []interface{} when decoded, typed slices when built in Go
+	rawBytes, err := json.Marshal(raw)
+	if err != nil {
+		return requirements, fmt.Errorf("invalid %s: %v", ExtraKeyAssets, err)
+	}
+	var legs []map[string]interface{}
+	if err := json.Unmarshal(rawBytes, &legs); err != nil {
+		return requirements, fmt.Errorf("invalid %s: expected a list of asset/amount pairs", ExtraKeyAssets)
+	}
+	for i, leg := range legs {
+		asset, _ := leg["asset"].(string)
+		if asset == "" {
+			return requirements, fmt.Errorf("%s[%d]: asset is required", ExtraKeyAssets, i)
+		}
+		if leg["asset"], err = r.Identifier(chainID, asset); err != nil {
+			return requirements, fmt.Errorf("%s[%d]: %v", ExtraKeyAssets, i, err)
+		}
+	}
+
+	extra := make(map[string]interface{}, len(requirements.Extra))
+	for k, v := range requirements.Extra {
+		extra[k] = v
+	}
+	extra[ExtraKeyAssets] = legs
+	requirements.Extra = extra
+	return requirements, nil
+}
+
+// Resolve resolves asset like Identifier, and also needs its decimals:
+// unknown identifiers are fetched through the chain's TokenFetcher
+func (r *TokenRegistry) Resolve(ctx context.Context, chainID string, asset string) (TokenInfo, error) {
+	info, known, err := r.lookup(chainID, asset)
+	if err != nil || known {
+		return info, err
+	}
+	identifier, err := r.Identifier(chainID, asset)
+	if err != nil {
+		return TokenInfo{}, err
+	}
+
+	key := chainID + "/" + identifier
+	r.mu.RLock()
+	info, cached := r.fetched[key]
+	fetcher := r.fetchers[chainID]
+	r.mu.RUnlock()
+	if cached {
+		return info, nil
+	}
+	if fetcher == nil {
+		return TokenInfo{}, fmt.Errorf("unknown decimals for token %s on chain %s", identifier, chainID)
+	}
+
+	info, err = fetcher.GetToken(ctx, identifier)
+	if err != nil {
+		return TokenInfo{}, fmt.Errorf("failed to fetch token %s: %v", identifier, err)
+	}
+	if info.Identifier != identifier {
+		return TokenInfo{}, fmt.Errorf("fetched token %s for %s", info.Identifier, identifier)
+	}
+
+	r.mu.Lock()
+	r.fetched[key] = info
+	r.mu.Unlock()
+	return info, nil
+}
+
+// lookup finds asset among the registered names and identifiers. An asset registered
+// only for other chains is an error: a mainnet identifier does not exist on devnet.
+func (r *TokenRegistry) lookup(chainID string, asset string) (TokenInfo, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if token, ok := r.names[strings.ToUpper(asset)]; ok {
+		identifier, ok := token.Identifiers[chainID]
+		if !ok {
+			identifier, ok = token.Identifiers[AnyChain]
+		}
+		if !ok {
+			return TokenInfo{}, false, fmt.Errorf("token %s is not available on chain %s", token.Name, chainID)
+		}
+		return TokenInfo{Identifier: identifier, Decimals: token.Decimals}, true, nil
+	}
+
+	// The native token, also as it appears inside multi-transfers (EGLD-000000)
+	if asset == "EGLD" || asset == EGLDTokenIdentifier {
+		return TokenInfo{Identifier: asset, Decimals: 18}, true, nil
+	}
+
+	chains := r.byID[asset]
+	token, ok := chains[chainID]
+	if !ok {
+		token, ok = chains[AnyChain]
+	}
+	if ok {
+		return TokenInfo{Identifier: asset, Decimals: token.Decimals}, true, nil
+	}
+	if len(chains) > 0 {
+		return TokenInfo{}, false, fmt.Errorf("token %s is not registered on chain %s", asset, chainID)
+	}
+	return TokenInfo{}, false, nil
+}
@@ -0,0 +1,188 @@
+package multiversx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/coinbase/x402/go/types"
+)
+
+func TestTokenRegistry_Identifier(t *testing.T) {
+	r := NewDefaultTokenRegistry()
+
+	valid := []struct {
+		chainID  string
+		asset    string
+		expected string
+	}{
+		{"1", "USDC", "USDC-c76f1f"},
+		{"D", "usdc", "USDC-350c4e"},
+		{"1", "WEGLD", "WEGLD-bd4d79"},
+		{"T", "EGLD", "EGLD"},
+		{"T", EGLDTokenIdentifier, EGLDTokenIdentifier},
+		{"1", "USDT-f8c08c", "USDT-f8c08c"},
+		{"D", "ABC-123456", "ABC-123456"},       // unknown identifiers pass through
+		{"D", "NFT-123456-0a", "NFT-123456-0a"}, // NFTs too
+	}
+	for _, tc := range valid {
+		got, err := r.Identifier(tc.chainID, tc.asset)
+		if err != nil || got != tc.expected {
+			t.Errorf("Identifier(%s, %s) = %q (%v), expected %s", tc.chainID, tc.asset, got, err, tc.expected)
+		}
+	}
+
+	invalid := []struct {
+		chainID string
+		asset   string
+	}{
+		{"T", "USDC"},        // no USDC on testnet
+		{"D", "USDC-c76f1f"}, // the mainnet identifier
+		{"1", "DOGE"},        // neither a name nor an identifier
+		{"1", "ABC-123456-zz"},
+	}
+	for _, tc := range invalid {
+		if got, err := r.Identifier(tc.chainID, tc.asset); err == nil {
+			t.Errorf("Identifier(%s, %s) = %q, expected an error", tc.chainID, tc.asset, got)
+		}
+	}
+}
+
+func TestTokenRegistry_Register(t *testing.T) {
+	r, err := NewTokenRegistry(Token{Name: "MEX", Decimals: 18, Identifiers: map[string]string{"1": "MEX-455c57"}})
+	if err != nil {
+		t.Fatalf("NewTokenRegistry: %v", err)
+	}
+	info, err := r.Resolve(context.Background(), "1", "mex")
+	if err != nil || info != (TokenInfo{Identifier: "MEX-455c57", Decimals: 18}) {
+		t.Errorf("Unexpected token %+v (%v)", info, err)
+	}
+	if _, err := r.Identifier("1", "USDC"); err == nil {
+		t.Error("A registry without the well-known tokens must not know USDC")
+	}
+
+	// Re-registering a name replaces its identifiers
+	r.Register(Token{Name: "MEX", Decimals: 18, Identifiers: map[string]string{"D": "MEX-a659d0"}})
+	if _, err := r.Identifier("1", "MEX"); err == nil {
+		t.Error("Expected MEX to be gone from mainnet")
+	}
+	if got, _ := r.Identifier("1", "MEX-455c57"); got != "MEX-455c57" {
+		t.Errorf("The old identifier is unknown again, got %q", got)
+	}
+
+	invalid := []Token{
+		{Decimals: 6, Identifiers: map[string]string{"1": "ABC-123456"}},
+		{Name: "ABC", Decimals: 6},
+		{Name: "ABC", Decimals: 19, Identifiers: map[string]string{"1": "ABC-123456"}},
+		{Name: "ABC", Decimals: 6, Identifiers: map[string]string{"1": "ABC--123456"}},
+	}
+	for _, token := range invalid {
+		if err := r.Register(token); err == nil {
+			t.Errorf("Expected an error registering %+v", token)
+		}
+	}
+}
+
+func TestTokenRegistry_Clone(t *testing.T) {
+	r := NewDefaultTokenRegistry()
+	c := r.Clone()
+
+	c.Register(Token{Name: "MEX", Decimals: 18, Identifiers: map[string]string{"1": "MEX-455c57"}})
+	c.Register(Token{Name: "USDC", Decimals: 6, Identifiers: map[string]string{"T": "USDC-79d9a4"}})
+	if _, err := r.Identifier("1", "MEX"); err == nil {
+		t.Error("A token registered on the clone must not show in the original")
+	}
+	if got, err := r.Identifier("D", "USDC"); err != nil || got != "USDC-350c4e" {
+		t.Errorf("Replacing a token on the clone changed the original: %q (%v)", got, err)
+	}
+	if got, err := c.Identifier("T", "USDC"); err != nil || got != "USDC-79d9a4" {
+		t.Errorf("Expected the clone's USDC, got %q (%v)", got, err)
+	}
+}
+
+func TestTokenRegistry_Fetch(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/esdt/ABC-123456" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"data":null,"error":"token not found","code":"not_found"}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"tokenData":{"tokenIdentifier":"ABC-123456","decimals":8}},"code":"successful"}`))
+	}))
+	defer server.Close()
+
+	r := NewDefaultTokenRegistry()
+	ctx := context.Background()
+	if _, err := r.Resolve(ctx, "D", "ABC-123456"); err == nil {
+		t.Error("Expected unknown decimals without a fetcher")
+	}
+
+	r.SetFetcher("D", NewGatewayProvider(server.URL, nil))
+	for i := 0; i < 2; i++ {
+		info, err := r.Resolve(ctx, "D", "ABC-123456")
+		if err != nil || info != (TokenInfo{Identifier: "ABC-123456", Decimals: 8}) {
+			t.Errorf("Unexpected token %+v (%v)", info, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected the fetched token to be cached, got %d calls", calls)
+	}
+
+	if _, err := r.Resolve(ctx, "D", "XYZ-123456"); err == nil {
+		t.Error("Expected an error for a token the gateway does not know")
+	}
+	if _, err := r.Resolve(ctx, "1", "ABC-123456"); err == nil {
+		t.Error("The devnet fetcher must not answer for mainnet")
+	}
+	// Known tokens never hit the gateway
+	if info, err := r.Resolve(ctx, "D", "USDC"); err != nil || info.Decimals != 6 || calls != 2 {
+		t.Errorf("Unexpected token %+v (%v), %d calls", info, err, calls)
+	}
+}
+
+func TestTokenRegistry_ResolveAssets(t *testing.T) {
+	r := NewDefaultTokenRegistry()
+	extra := map[string]interface{}{
+		ExtraKeyResourceID: "inv_1",
+		ExtraKeyAssets:     []map[string]string{{"asset": "usdc", "amount": "100"}, {"asset": "ABC-123456", "amount": "1"}},
+	}
+	req := types.PaymentRequirements{Asset: "EGLD", Amount: "1000", Extra: extra}
+
+	resolved, err := r.ResolveAssets("D", req)
+	if err != nil {
+		t.Fatalf("ResolveAssets: %v", err)
+	}
+	legs, err := RequiredTransfers(resolved)
+	if err != nil {
+		t.Fatalf("RequiredTransfers: %v", err)
+	}
+	var identifiers []string
+	for _, leg := range legs {
+		identifiers = append(identifiers, leg.Identifier)
+	}
+	if !reflect.DeepEqual(identifiers, []string{EGLDTokenIdentifier, "USDC-350c4e", "ABC-123456"}) {
+		t.Errorf("Unexpected legs %v", identifiers)
+	}
+	if resolved.Extra[ExtraKeyResourceID] != "inv_1" {
+		t.Errorf("Other extra fields must be kept: %v", resolved.Extra)
+	}
+	if extra[ExtraKeyAssets].([]map[string]string)[0]["asset"] != "usdc" {
+		t.Error("The caller's Extra must not be modified")
+	}
+
+	// A leg missing on the chain, or without an asset
+	for _, assets := range []interface{}{
+		[]map[string]string{{"asset": "USDT", "amount": "1"}},
+		[]map[string]string{{"amount": "1"}},
+		"USDC",
+	} {
+		req.Extra = map[string]interface{}{ExtraKeyAssets: assets}
+		if _, err := r.ResolveAssets("D", req); err == nil {
+			t.Errorf("Expected an error for assets %v", assets)
+		}
+	}
+}
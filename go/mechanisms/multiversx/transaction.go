@@ -0,0 +1,164 @@
+package multiversx
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Transaction option bits
+const (
+	// TransactionOptionSignedWithHash: signatures are made over keccak256(serialized tx)
+	TransactionOptionSignedWithHash uint32 = 1 << 0
+	// TransactionOptionGuarded: the transaction is co-signed by the account guardian
+	TransactionOptionGuarded uint32 = 1 << 1
+)
+
+// Transaction is a MultiversX transaction as carried in the x402 payload.
+// Data is kept as plain text here; it is base64 encoded on the wire to the node
+// and in the bytes used for signing.
+type Transaction struct {
+	Nonce            uint64 `json:"nonce"`
+	Value            string `json:"value"`
+	Receiver         string `json:"receiver"`
+	Sender           string `json:"sender"`
+	SenderUsername   string `json:"senderUsername,omitempty"`
+	ReceiverUsername string `json:"receiverUsername,omitempty"`
+	GasPrice         uint64 `json:"gasPrice"`
+	GasLimit         uint64 `json:"gasLimit"`
+	Data             string `json:"data"`
+	ChainID          string `json:"chainID"`
+	Version          uint32 `json:"version"`
+	Options          uint32 `json:"options"`
+	Signature        string `json:"signature"` // Hex encoded
+
+	// Guarded accounts: the guardian co-signs the same bytes as the sender
+	Guardian          string `json:"guardian,omitempty"`
+	GuardianSignature string `json:"guardianSignature,omitempty"` // Hex encoded
+
+	// Relayed V3: the relayer pays the gas and co-signs the same bytes as the sender
+	Relayer          string `json:"relayer,omitempty"`
+	RelayerSignature string `json:"relayerSignature,omitempty"` // Hex encoded
+}
+
+// signableTransaction mirrors the node's FrontendTransaction without signatures.
+// Field order and omitempty rules are part of the signed bytes and must not change.
+type signableTransaction struct {
+	Nonce            uint64 `json:"nonce"`
+	Value            string `json:"value"`
+	Receiver         string `json:"receiver"`
+	Sender           string `json:"sender"`
+	SenderUsername   string `json:"senderUsername,omitempty"`   // base64
+	ReceiverUsername string `json:"receiverUsername,omitempty"` // base64
+	GasPrice         uint64 `json:"gasPrice"`
+	GasLimit         uint64 `json:"gasLimit"`
+	Data             string `json:"data,omitempty"` // base64
+	ChainID          string `json:"chainID"`
+	Version          uint32 `json:"version"`
+	Options          uint32 `json:"options,omitempty"`
+	Guardian         string `json:"guardian,omitempty"`
+	Relayer          string `json:"relayer,omitempty"`
+}
+
+// IsRelayed reports whether the transaction is a Relayed V3 transaction
+func (tx *Transaction) IsRelayed() bool {
+	return tx.Relayer != ""
+}
+
+// IsGuarded reports whether the transaction is flagged as guarded
+func (tx *Transaction) IsGuarded() bool {
+	return tx.Options&TransactionOptionGuarded != 0
+}
+
+// IsSignedWithHash reports whether signatures are made over the hash of the serialized transaction.
+// The option is only honoured by the node starting with version 2.
+func (tx *Transaction) IsSignedWithHash() bool {
+	return tx.Version >= 2 && tx.Options&TransactionOptionSignedWithHash != 0
+}
+
+// SerializeForSigning returns the canonical JSON serialization of the transaction,
+// byte-for-byte identical to the node and the official SDKs. Signatures are excluded.
+func (tx *Transaction) SerializeForSigning() ([]byte, error) {
+	value := tx.Value
+	if value == "" {
+		value = "0"
+	}
+
+	signable := signableTransaction{
+		Nonce:            tx.Nonce,
+		Value:            value,
+		Receiver:         tx.Receiver,
+		Sender:           tx.Sender,
+		SenderUsername:   encodeBase64(tx.SenderUsername),
+		ReceiverUsername: encodeBase64(tx.ReceiverUsername),
+		GasPrice:         tx.GasPrice,
+		GasLimit:         tx.GasLimit,
+		Data:             encodeBase64(tx.Data),
+		ChainID:          tx.ChainID,
+		Version:          tx.Version,
+		Options:          tx.Options,
+		Guardian:         tx.Guardian,
+		Relayer:          tx.Relayer,
+	}
+
+	// Match JSON.stringify: no HTML escaping and no trailing newline
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(signable); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// BytesForSigning returns the bytes the sender, guardian and relayer sign:
+// the serialized transaction, or its keccak256 hash when the sign-with-hash option is set.
+func (tx *Transaction) BytesForSigning() ([]byte, error) {
+	serialized, err := tx.SerializeForSigning()
+	if err != nil {
+		return nil, err
+	}
+	if !tx.IsSignedWithHash() {
+		return serialized, nil
+	}
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write(serialized)
+	return h.Sum(nil), nil
+}
+
+func encodeBase64(s string) string {
+	if s == "" {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+// GatewayRequest maps the transaction to the gateway transaction body.
+// /transaction/simulate, /transaction/send and /transaction/cost accept the same shape.
+func (tx *Transaction) GatewayRequest() SimulationRequest {
+	return SimulationRequest{
+		Nonce:     tx.Nonce,
+		Value:     tx.Value,
+		Receiver:  tx.Receiver,
+		Sender:    tx.Sender,
+		GasPrice:  tx.GasPrice,
+		GasLimit:  tx.GasLimit,
+		Data:      encodeBase64(tx.Data),
+		ChainID:   tx.ChainID,
+		Version:   tx.Version,
+		Options:   tx.Options,
+		Signature: tx.Signature,
+
+		SenderUsername:   encodeBase64(tx.SenderUsername),
+		ReceiverUsername: encodeBase64(tx.ReceiverUsername),
+
+		Guardian:          tx.Guardian,
+		GuardianSignature: tx.GuardianSignature,
+
+		Relayer:          tx.Relayer,
+		RelayerSignature: tx.RelayerSignature,
+	}
+}
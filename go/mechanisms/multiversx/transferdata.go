@@ -0,0 +1,284 @@
+package multiversx
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ESDT built-in transfer functions
+const (
+	FunctionESDTTransfer         = "ESDTTransfer"
+	FunctionESDTNFTTransfer      = "ESDTNFTTransfer"
+	FunctionMultiESDTNFTTransfer = "MultiESDTNFTTransfer"
+)
+
+// TokenTransfer is one token leg of an ESDT transfer
+type TokenTransfer struct {
+	Identifier string   // e.g. "USDC-c76f1f", or "EGLD-000000" for EGLD inside MultiESDTNFTTransfer
+	Nonce      uint64   // 0 for fungible tokens, the NFT/SFT nonce otherwise
+	Amount     *big.Int // atomic units
+}
+
+// TokenIdentifier is an asset as written in payment requirements: a fungible token
+// ("USDC-c76f1f") or a single NFT/SFT of a collection, with the nonce as hex suffix ("TICKET-abcdef-0a").
+type TokenIdentifier struct {
+	Collection string // token identifier as used in the transfer data
+	Nonce      uint64 // 0 for fungible tokens
+}
+
+// ParseTokenIdentifier splits an asset into collection and nonce
+func ParseTokenIdentifier(asset string) (TokenIdentifier, error) {
+	parts := strings.Split(asset, "-")
+	for _, p := range parts {
+		if p == "" {
+			return TokenIdentifier{}, fmt.Errorf("invalid token identifier %q", asset)
+		}
+	}
+	switch len(parts) {
+	case 1, 2:
+		return TokenIdentifier{Collection: asset}, nil
+	case 3:
+		if err := checkHexArg(parts[2]); err != nil {
+			return TokenIdentifier{}, fmt.Errorf("invalid nonce in token identifier %q: %v", asset, err)
+		}
+		nonce, err := decodeUint(parts[2])
+		if err != nil || nonce == 0 {
+			return TokenIdentifier{}, fmt.Errorf("invalid nonce in token identifier %q", asset)
+		}
+		return TokenIdentifier{Collection: parts[0] + "-" + parts[1], Nonce: nonce}, nil
+	default:
+		return TokenIdentifier{}, fmt.Errorf("invalid token identifier %q", asset)
+	}
+}
+
+// IsNonFungible reports whether the identifier names a single NFT/SFT nonce
+func (t TokenIdentifier) IsNonFungible() bool {
+	return t.Nonce > 0
+}
+
+// String returns the identifier in requirement form
+func (t TokenIdentifier) String() string {
+	if t.Nonce == 0 {
+		return t.Collection
+	}
+	return t.Collection + "-" + encodeUint(t.Nonce)
+}
+
+// TransferData is the decoded data field of an ESDT built-in transfer:
+//
+//	ESDTTransfer@<token>@<amount>[@<function>@<args>...]                        (receiver: tx receiver)
+//	ESDTNFTTransfer@<token>@<nonce>@<amount>@<dest>[@<function>@<args>...]      (tx receiver: sender)
+//	MultiESDTNFTTransfer@<dest>@<n>(@<token>@<nonce>@<amount>){n}[@<function>@<args>...]
+//
+// Every argument, including the trailing function name, is hex encoded.
+type TransferData struct {
+	Function  string
+	Receiver  Address // empty for ESDTTransfer, where the transaction receiver is the destination
+	Transfers []TokenTransfer
+
+	// Optional smart contract call (or free-form binding such as a resourceId) after the transfers
+	CallFunction string
+	CallArgs     [][]byte
+}
+
+// Encode builds the transaction data field
+func (d TransferData) Encode() (string, error) {
+	if len(d.Transfers) == 0 {
+		return "", errors.New("transfer data needs at least one transfer")
+	}
+	for _, t := range d.Transfers {
+		if t.Identifier == "" {
+			return "", errors.New("transfer token identifier is empty")
+		}
+		if t.Amount == nil || t.Amount.Sign() < 0 {
+			return "", fmt.Errorf("invalid amount for %s", t.Identifier)
+		}
+	}
+
+	args := []string{d.Function}
+	switch d.Function {
+	case FunctionESDTTransfer:
+		if len(d.Transfers) != 1 || d.Transfers[0].Nonce != 0 {
+			return "", errors.New("ESDTTransfer carries exactly one fungible transfer")
+		}
+		t := d.Transfers[0]
+		args = append(args, hex.EncodeToString([]byte(t.Identifier)), encodeBigInt(t.Amount))
+	case FunctionESDTNFTTransfer:
+		if len(d.Transfers) != 1 {
+			return "", errors.New("ESDTNFTTransfer carries exactly one transfer")
+		}
+		if d.Receiver.IsEmpty() {
+			return "", errors.New("ESDTNFTTransfer needs a destination")
+		}
+		t := d.Transfers[0]
+		args = append(args, hex.EncodeToString([]byte(t.Identifier)), encodeUint(t.Nonce), encodeBigInt(t.Amount), d.Receiver.Hex())
+	case FunctionMultiESDTNFTTransfer:
+		if d.Receiver.IsEmpty() {
+			return "", errors.New("MultiESDTNFTTransfer needs a destination")
+		}
+		args = append(args, d.Receiver.Hex(), encodeUint(uint64(len(d.Transfers))))
+		for _, t := range d.Transfers {
+			args = append(args, hex.EncodeToString([]byte(t.Identifier)), encodeUint(t.Nonce), encodeBigInt(t.Amount))
+		}
+	default:
+		return "", fmt.Errorf("unsupported transfer function: %q", d.Function)
+	}
+
+	if d.CallFunction != "" {
+		args = append(args, hex.EncodeToString([]byte(d.CallFunction)))
+	} else if len(d.CallArgs) > 0 {
+		return "", errors.New("call arguments without a function")
+	}
+	for _, a := range d.CallArgs {
+		args = append(args, hex.EncodeToString(a))
+	}
+
+	return strings.Join(args, "@"), nil
+}
+
+// DecodeTransferData parses an ESDT transfer data field. hrp is used for the destination
+// address (empty defaults to DefaultHRP). Every argument must be well-formed hex.
+func DecodeTransferData(data string, hrp string) (*TransferData, error) {
+	parts := strings.Split(data, "@")
+	d := &TransferData{Function: parts[0]}
+	args := parts[1:]
+	for i, a := range args {
+		if err := checkHexArg(a); err != nil {
+			return nil, fmt.Errorf("argument %d: %v", i+1, err)
+		}
+	}
+
+	var rest []string
+	switch d.Function {
+	case FunctionESDTTransfer:
+		if len(args) < 2 {
+			return nil, errors.New("ESDTTransfer needs token and amount")
+		}
+		t, err := decodeTokenTransfer(args[0], "", args[1])
+		if err != nil {
+			return nil, err
+		}
+		d.Transfers = []TokenTransfer{t}
+		rest = args[2:]
+	case FunctionESDTNFTTransfer:
+		if len(args) < 4 {
+			return nil, errors.New("ESDTNFTTransfer needs token, nonce, amount and destination")
+		}
+		t, err := decodeTokenTransfer(args[0], args[1], args[2])
+		if err != nil {
+			return nil, err
+		}
+		d.Transfers = []TokenTransfer{t}
+		if d.Receiver, err = AddressFromHex(args[3], hrp); err != nil {
+			return nil, fmt.Errorf("invalid destination: %v", err)
+		}
+		rest = args[4:]
+	case FunctionMultiESDTNFTTransfer:
+		if len(args) < 2 {
+			return nil, errors.New("MultiESDTNFTTransfer needs destination and transfer count")
+		}
+		var err error
+		if d.Receiver, err = AddressFromHex(args[0], hrp); err != nil {
+			return nil, fmt.Errorf("invalid destination: %v", err)
+		}
+		count, err := decodeUint(args[1])
+		if err != nil || count == 0 {
+			return nil, fmt.Errorf("invalid transfer count %q", args[1])
+		}
+		// Compare against the available triples: 3*count may overflow
+		if count > uint64((len(args)-2)/3) {
+			return nil, fmt.Errorf("expected %d transfers, data is too short", count)
+		}
+		for i := uint64(0); i < count; i++ {
+			base := 2 + 3*i
+			t, err := decodeTokenTransfer(args[base], args[base+1], args[base+2])
+			if err != nil {
+				return nil, fmt.Errorf("transfer %d: %v", i+1, err)
+			}
+			d.Transfers = append(d.Transfers, t)
+		}
+		rest = args[2+3*count:]
+	default:
+		return nil, fmt.Errorf("not an ESDT transfer: %q", d.Function)
+	}
+
+	if len(rest) > 0 {
+		fn, _ := hex.DecodeString(rest[0])
+		if len(fn) == 0 {
+			return nil, errors.New("empty call function")
+		}
+		d.CallFunction = string(fn)
+		for _, a := range rest[1:] {
+			arg, _ := hex.DecodeString(a)
+			d.CallArgs = append(d.CallArgs, arg)
+		}
+	}
+	return d, nil
+}
+
+// IsESDTTransferData reports whether data starts with one of the ESDT built-in transfer functions
+func IsESDTTransferData(data string) bool {
+	switch strings.SplitN(data, "@", 2)[0] {
+	case FunctionESDTTransfer, FunctionESDTNFTTransfer, FunctionMultiESDTNFTTransfer:
+		return true
+	}
+	return false
+}
+
+func decodeTokenTransfer(tokenHex string, nonceHex string, amountHex string) (TokenTransfer, error) {
+	token, _ := hex.DecodeString(tokenHex)
+	if len(token) == 0 {
+		return TokenTransfer{}, errors.New("empty token identifier")
+	}
+	nonce, err := decodeUint(nonceHex)
+	if err != nil {
+		return TokenTransfer{}, fmt.Errorf("invalid nonce for %s: %v", token, err)
+	}
+	amountBytes, _ := hex.DecodeString(amountHex)
+	return TokenTransfer{
+		Identifier: string(token),
+		Nonce:      nonce,
+		Amount:     new(big.Int).SetBytes(amountBytes),
+	}, nil
+}
+
+// checkHexArg accepts an even-length lowercase hex string (empty encodes zero / empty bytes)
+func checkHexArg(arg string) error {
+	if len(arg)%2 != 0 {
+		return fmt.Errorf("odd length hex %q", arg)
+	}
+	for _, c := range arg {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return fmt.Errorf("invalid hex %q", arg)
+		}
+	}
+	return nil
+}
+
+func decodeUint(arg string) (uint64, error) {
+	b, err := hex.DecodeString(arg)
+	if err != nil {
+		return 0, err
+	}
+	n := new(big.Int).SetBytes(b)
+	if !n.IsUint64() {
+		return 0, fmt.Errorf("value %s overflows uint64", n)
+	}
+	return n.Uint64(), nil
+}
+
+// encodeBigInt returns the minimal even-length hex of n; zero is "00"
+func encodeBigInt(n *big.Int) string {
+	h := n.Text(16)
+	if len(h)%2 != 0 {
+		h = "0" + h
+	}
+	return h
+}
+
+func encodeUint(n uint64) string {
+	return encodeBigInt(new(big.Int).SetUint64(n))
+}
@@ -0,0 +1,137 @@
+package multiversx
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestTransferData_RoundTrip(t *testing.T) {
+	bob, _ := AddressFromBech32(bobAddress)
+
+	tests := []struct {
+		name    string
+		data    TransferData
+		encoded string
+	}{
+		{
+			name: "ESDTTransfer",
+			data: TransferData{
+				Function:  FunctionESDTTransfer,
+				Transfers: []TokenTransfer{{Identifier: "USDC-c76f1f", Amount: big.NewInt(1000000)}},
+			},
+			encoded: "ESDTTransfer@555344432d633736663166@0f4240",
+		},
+		{
+			name: "ESDTNFTTransfer",
+			data: TransferData{
+				Function:  FunctionESDTNFTTransfer,
+				Receiver:  bob,
+				Transfers: []TokenTransfer{{Identifier: "TICKET-abcdef", Nonce: 10, Amount: big.NewInt(1)}},
+			},
+			encoded: "ESDTNFTTransfer@5449434b45542d616263646566@0a@01@" + bobPubKeyHex,
+		},
+		{
+			name: "MultiESDTNFTTransfer with call",
+			data: TransferData{
+				Function: FunctionMultiESDTNFTTransfer,
+				Receiver: bob,
+				Transfers: []TokenTransfer{
+					{Identifier: "USDC-c76f1f", Amount: big.NewInt(100)},
+					{Identifier: "TICKET-abcdef", Nonce: 300, Amount: big.NewInt(2)},
+				},
+				CallFunction: "inv_123",
+				CallArgs:     [][]byte{{0x01, 0x02}},
+			},
+			encoded: "MultiESDTNFTTransfer@" + bobPubKeyHex + "@02@555344432d633736663166@00@64@5449434b45542d616263646566@012c@02@696e765f313233@0102",
+		},
+	}
+
+	for _, tc := range tests {
+		encoded, err := tc.data.Encode()
+		if err != nil {
+			t.Fatalf("%s: Encode: %v", tc.name, err)
+		}
+		if encoded != tc.encoded {
+			t.Errorf("%s: expected\n%s\ngot\n%s", tc.name, tc.encoded, encoded)
+		}
+
+		decoded, err := DecodeTransferData(encoded, "")
+		if err != nil {
+			t.Fatalf("%s: Decode: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(*decoded, tc.data) {
+			t.Errorf("%s: round trip mismatch:\n%+v\n%+v", tc.name, *decoded, tc.data)
+		}
+	}
+}
+
+func TestDecodeTransferData_Strict(t *testing.T) {
+	dest := bobPubKeyHex
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not a transfer", "transfer@01"},
+		{"odd hex", "ESDTTransfer@555344432d633736663166@f4240"},
+		{"uppercase hex", "ESDTTransfer@555344432D633736663166@0f4240"},
+		{"missing amount", "ESDTTransfer@555344432d633736663166"},
+		{"empty token", "ESDTTransfer@@0f4240"},
+		{"short destination", "MultiESDTNFTTransfer@abcd@01@555344432d633736663166@00@64"},
+		{"count exceeds data", "MultiESDTNFTTransfer@" + dest + "@02@555344432d633736663166@00@64"},
+		{"zero count", "MultiESDTNFTTransfer@" + dest + "@00"},
+		{"count overflowing 3*count", "MultiESDTNFTTransfer@" + dest + "@5555555555555556@555344432d633736663166@00@64"},
+		{"nonce overflow", "MultiESDTNFTTransfer@" + dest + "@01@555344432d633736663166@010000000000000000@64"},
+		{"empty call function", "ESDTTransfer@555344432d633736663166@0f4240@@01"},
+	}
+
+	for _, tc := range tests {
+		if _, err := DecodeTransferData(tc.data, ""); err == nil {
+			t.Errorf("%s: expected error for %s", tc.name, tc.data)
+		}
+	}
+
+	// Zero may be encoded as "" or "00"
+	d, err := DecodeTransferData("MultiESDTNFTTransfer@"+dest+"@01@555344432d633736663166@@64", "")
+	if err != nil || d.Transfers[0].Nonce != 0 {
+		t.Errorf("Expected empty nonce to decode as zero, got %v", err)
+	}
+}
+
+func TestParseTokenIdentifier(t *testing.T) {
+	tests := []struct {
+		asset    string
+		expected TokenIdentifier
+		wantErr  bool
+	}{
+		{asset: "USDC-c76f1f", expected: TokenIdentifier{Collection: "USDC-c76f1f"}},
+		{asset: "EGLD-000000", expected: TokenIdentifier{Collection: "EGLD-000000"}},
+		{asset: "TICKET-abcdef-0a", expected: TokenIdentifier{Collection: "TICKET-abcdef", Nonce: 10}},
+		{asset: "TICKET-abcdef-012c", expected: TokenIdentifier{Collection: "TICKET-abcdef", Nonce: 300}},
+		{asset: "TICKET-abcdef-00", wantErr: true}, // nonce 0 is the fungible form
+		{asset: "TICKET-abcdef-a", wantErr: true},  // odd-length nonce
+		{asset: "TICKET-abcdef-0A", wantErr: true}, // uppercase nonce
+		{asset: "TICKET-abcdef-zz", wantErr: true}, // not hex
+		{asset: "TICKET--0a", wantErr: true},       // empty part
+		{asset: "A-abcdef-01-02", wantErr: true},   // too many parts
+	}
+
+	for _, tc := range tests {
+		got, err := ParseTokenIdentifier(tc.asset)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %+v", tc.asset, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.asset, err)
+		}
+		if got != tc.expected {
+			t.Errorf("%s: expected %+v, got %+v", tc.asset, tc.expected, got)
+		}
+		if got.String() != tc.asset {
+			t.Errorf("%s: String() gave %s", tc.asset, got.String())
+		}
+	}
+}
@@ -1,32 +1,56 @@
 package multiversx
 
-import "math/big"
+import (
+	"fmt"
+	"math/big"
+)
 
 // SchemeExact is the identifier for the exact payment scheme
 const SchemeExact = "multiversx-exact-v1"
 
+const (
+	// RelayedTransactionVersion is the minimum transaction version carrying a relayer (Relayed V3)
+	RelayedTransactionVersion = 2
+
+	// RelayerGasOverhead is the extra gas a Relayed V3 transaction consumes on top of the inner transfer
+	RelayerGasOverhead = 50000
+
+	// ExtraKeyRelayer is the requirements Extra key advertising the facilitator's relayer address
+	ExtraKeyRelayer = "relayer"
+)
+
 // NetworkConfig holds configuration for a MultiversX network
 type NetworkConfig struct {
 	APIUrl  string
 	ChainID string
+
+	// Facilitator settings for the network (optional)
+	Relayer   FacilitatorMultiversXSigner // Relayed V3 gas payer
+	GasPolicy *GasPolicy                  // limits on the gas the relayer pays (nil: DefaultGasPolicy)
+}
+
+// Account is the on-chain state of an address
+type Account struct {
+	Address  string `json:"address"`
+	Nonce    uint64 `json:"nonce"`
+	Balance  string `json:"balance"`
+	Username string `json:"username,omitempty"`
+}
+
+// NetworkParameters are the protocol parameters published by /network/config
+type NetworkParameters struct {
+	ChainID               string
+	MinGasLimit           uint64
+	GasPerDataByte        uint64
+	MinGasPrice           uint64
+	GasPriceModifier      float64
+	MinTransactionVersion uint32
 }
 
 // ExactRelayedPayload matches the JSON sent by the Client (V3/Relayed)
 type ExactRelayedPayload struct {
-	Scheme string `json:"scheme"`
-	Data   struct {
-		Nonce     uint64 `json:"nonce"`
-		Value     string `json:"value"`
-		Receiver  string `json:"receiver"`
-		Sender    string `json:"sender"`
-		GasPrice  uint64 `json:"gasPrice"`
-		GasLimit  uint64 `json:"gasLimit"`
-		Data      string `json:"data"`
-		ChainID   string `json:"chainID"`
-		Version   uint32 `json:"version"`
-		Options   uint32 `json:"options"`
-		Signature string `json:"signature"` // Hex encoded
-	} `json:"data"`
+	Scheme string      `json:"scheme"`
+	Data   Transaction `json:"data"`
 }
 
 // SimulationRequest represents the body for /transaction/simulate
@@ -40,7 +64,17 @@ type SimulationRequest struct {
 	Data      string `json:"data,omitempty"`
 	ChainID   string `json:"chainID"`
 	Version   uint32 `json:"version"`
+	Options   uint32 `json:"options,omitempty"`
 	Signature string `json:"signature"`
+
+	SenderUsername   string `json:"senderUsername,omitempty"`   // base64
+	ReceiverUsername string `json:"receiverUsername,omitempty"` // base64
+
+	Guardian          string `json:"guardian,omitempty"`
+	GuardianSignature string `json:"guardianSignature,omitempty"`
+
+	Relayer          string `json:"relayer,omitempty"`
+	RelayerSignature string `json:"relayerSignature,omitempty"`
 }
 
 // SimulationResponse represents the response from /transaction/simulate
@@ -55,6 +89,78 @@ type SimulationResponse struct {
 	Code  string `json:"code"`
 }
 
+// SendTransactionResponse represents the response from /transaction/send
+type SendTransactionResponse struct {
+	Data struct {
+		TxHash string `json:"txHash"`
+	} `json:"data"`
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// TransactionStatusResponse represents the response from /transaction/{hash}/status
+type TransactionStatusResponse struct {
+	Data struct {
+		Status string `json:"status"`
+	} `json:"data"`
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// TransactionResponse represents the response from /transaction/{hash}?withResults=true
+type TransactionResponse struct {
+	Data struct {
+		Transaction TransactionOnNetwork `json:"transaction"`
+	} `json:"data"`
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// TransactionOnNetwork is the subset of the gateway transaction view needed to judge finality
+type TransactionOnNetwork struct {
+	Hash                              string                `json:"hash"`
+	Status                            string                `json:"status"`
+	SourceShard                       uint32                `json:"sourceShard"`
+	DestinationShard                  uint32                `json:"destinationShard"`
+	NotarizedAtDestinationInMetaNonce uint64                `json:"notarizedAtDestinationInMetaNonce"`
+	SmartContractResults              []SmartContractResult `json:"smartContractResults,omitempty"`
+	Logs                              *TransactionLogs      `json:"logs,omitempty"`
+}
+
+// SmartContractResult is a result generated while executing a transaction
+type SmartContractResult struct {
+	Hash          string           `json:"hash"`
+	Data          string           `json:"data"`
+	ReturnMessage string           `json:"returnMessage,omitempty"`
+	Logs          *TransactionLogs `json:"logs,omitempty"`
+}
+
+// TransactionLogs holds the events emitted by a transaction or a smart contract result
+type TransactionLogs struct {
+	Address string             `json:"address"`
+	Events  []TransactionEvent `json:"events"`
+}
+
+// TransactionEvent is a single log event. Topics and Data are base64 encoded.
+type TransactionEvent struct {
+	Address    string   `json:"address"`
+	Identifier string   `json:"identifier"`
+	Topics     []string `json:"topics"`
+	Data       string   `json:"data"`
+}
+
+// GatewayError is returned when the gateway answers but rejects the request
+// (e.g. a transaction refused by the mempool), as opposed to a transport failure.
+type GatewayError struct {
+	StatusCode int
+	Message    string
+	Code       string
+}
+
+func (e *GatewayError) Error() string {
+	return fmt.Sprintf("gateway rejected request (status %d, code: %s): %s", e.StatusCode, e.Code, e.Message)
+}
+
 // Helper to check big int logic
 func CheckBigInt(valStr string, expected string) bool {
 	val, ok := new(big.Int).SetString(valStr, 10)
@@ -37,25 +37,40 @@ func GetMultiversXChainId(network string) (string, error) {
 	return "", fmt.Errorf("unsupported network format: %s", network)
 }
 
+// NetworkFromChainID returns the CAIP-2 network of a chain ID, e.g. "multiversx:D"
+func NetworkFromChainID(chainID string) string {
+	return "multiversx:" + chainID
+}
+
 // IsValidAddress checks if addres is valid Bech32 with Checksum
 func IsValidAddress(address string) bool {
-	// 1. Basic length check (erd1... is 62)
-	if len(address) != 62 {
+	return IsValidAddressWithHRP(address, DefaultHRP)
+}
+
+// IsValidAddressWithHRP checks the address is valid Bech32 with the expected HRP (custom chains)
+func IsValidAddressWithHRP(address string, hrp string) bool {
+	// 1. Full Bech32 Decode & Checksum Verify (length and mixed case included)
+	addr, err := AddressFromBech32(address)
+	if err != nil {
 		return false
 	}
 
-	// 2. Full Bech32 Decode & Checksum Verify
-	hrp, _, err := DecodeBech32(address)
+	// 2. Check HRP
+	return addr.HRP() == hrp
+}
+
+// AddressesEqual reports whether two bech32 strings denote the same account.
+// Invalid addresses are never equal.
+func AddressesEqual(a string, b string) bool {
+	addrA, err := AddressFromBech32(a)
 	if err != nil {
 		return false
 	}
-
-	// 3. Check HRP is "erd"
-	if hrp != "erd" {
+	addrB, err := AddressFromBech32(b)
+	if err != nil {
 		return false
 	}
-
-	return true
+	return addrA.Equal(addrB)
 }
 
 // IsValidHex checks if string is valid hex (length check optional?)
@@ -77,3 +92,36 @@ func CheckAmount(amount string) (*big.Int, error) {
 	}
 	return i, nil
 }
+
+// ParseUnits converts a decimal amount ("0.05") to atomic units of a token with the given
+// decimals (5*10^16 for 18). Negative and zero amounts, and digits below the token's smallest
+// unit, are errors rather than being rounded away
+func ParseUnits(amount string, decimals int) (*big.Int, error) {
+	amount = strings.TrimSpace(amount)
+	whole, frac, hasPoint := strings.Cut(amount, ".")
+	if strings.HasPrefix(amount, "-") {
+		return nil, fmt.Errorf("negative amount: %s", amount)
+	}
+	if (whole == "" && frac == "") || !isDigits(whole) || !isDigits(frac) || (hasPoint && frac == "") {
+		return nil, fmt.Errorf("invalid amount: %q", amount)
+	}
+
+	significant := strings.TrimRight(frac, "0")
+	if len(significant) > decimals {
+		return nil, fmt.Errorf("amount %s has more than %d decimals", amount, decimals)
+	}
+	units, _ := new(big.Int).SetString(whole+significant+strings.Repeat("0", decimals-len(significant)), 10)
+	if units.Sign() <= 0 {
+		return nil, fmt.Errorf("amount must be positive: %s", amount)
+	}
+	return units, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
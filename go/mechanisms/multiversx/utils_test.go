@@ -82,3 +82,45 @@ func TestCheckAmount(t *testing.T) {
 		t.Errorf("Invalid string passed")
 	}
 }
+
+func TestParseUnits(t *testing.T) {
+	valid := []struct {
+		amount   string
+		decimals int
+		expected string
+	}{
+		{"0.05", 18, "50000000000000000"},
+		{"1", 6, "1000000"},
+		{"1.5", 6, "1500000"},
+		{".25", 6, "250000"},
+		{"0.010000", 2, "1"}, // trailing zeros lose nothing
+		{"42", 0, "42"},
+	}
+	for _, tc := range valid {
+		got, err := ParseUnits(tc.amount, tc.decimals)
+		if err != nil || got.String() != tc.expected {
+			t.Errorf("ParseUnits(%q, %d) = %v (%v), expected %s", tc.amount, tc.decimals, got, err, tc.expected)
+		}
+	}
+
+	invalid := []struct {
+		amount   string
+		decimals int
+	}{
+		{"0.0000001", 6}, // below the smallest unit
+		{"1.5", 0},
+		{"0", 18},
+		{"0.000", 18},
+		{"-1", 18},
+		{"", 18},
+		{"1.", 18},
+		{"1e3", 18},
+		{"1,5", 18},
+		{"+1", 18},
+	}
+	for _, tc := range invalid {
+		if got, err := ParseUnits(tc.amount, tc.decimals); err == nil {
+			t.Errorf("ParseUnits(%q, %d) = %v, expected an error", tc.amount, tc.decimals, got)
+		}
+	}
+}
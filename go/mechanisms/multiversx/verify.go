@@ -2,48 +2,77 @@ package multiversx
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
 	"fmt"
 
 	"github.com/coinbase/x402/go/types"
 )
 
-// VerifyUniversalSignature verifies the payment payload signature
-// For MultiversX, this implies:
-// 1. Validating the Ed25519 signature against the transaction bytes (if accessible/reconstructible).
-// 2. Simulating the transaction (Smart Contract wallets, or just general validity).
+// VerifyPayment checks the payload was signed by its sender and, optionally, would execute:
+//  1. The Ed25519 signature is checked locally against the canonical signing bytes
+//     (see Transaction.BytesForSigning), which rejects forged payloads offline.
+//  2. The simulator (/transaction/simulate) catches on-chain conditions (balance, nonce,
+//     guardian, relayer) the signature alone cannot prove.
 //
-// Since we don't effectively reconstruct the canonical JSON bytes locally easily without SDK canonicalizer,
-// we rely heavily on Simulation for the cryptographic proof (the node verifies the sig).
-//
-// However, if we CAN verify Ed25519 locally, we should.
-// But without the exact serialization logic from SDK, local verification is error-prone.
-// EVM has standard hashing (EIP-712). MultiversX has "canonical JSON of fields".
-// Recommendation: We stick to Simulation as the "Universal" verifier for MultiversX in this Go integration,
-// because implementing a Go Canonical JSON Serializer for MultiversX txs perfectly matching the node is complex.
-//
-// But we will expose a function that integrates the checks.
-
+// Pass a nil simulator to skip the second stage.
+// Rejections are returned as *VerificationError; simulator errors of any other type
+// (transport failures) are passed through unchanged.
 func VerifyPayment(ctx context.Context, payload ExactRelayedPayload, requirements types.PaymentRequirements, simulator func(ExactRelayedPayload) (string, error)) (bool, error) {
-	// 1. Static Checks
-	if payload.Data.Receiver != requirements.PayTo {
-		// Strict check depending on ESDT vs EGLD?
-		// Handled by caller usually, but good to have utilities.
+	// 1. Signature Presence
+	if payload.Data.Signature == "" {
+		return false, NewVerificationError(InvalidReasonInvalidSignature, "missing signature")
 	}
 
-	// 2. Signature Presence
-	if payload.Data.Signature == "" {
-		return false, fmt.Errorf("missing signature")
+	// 2. Local Ed25519 Verification
+	if err := VerifyTransactionSignature(payload.Data); err != nil {
+		return false, &VerificationError{Reason: InvalidReasonInvalidSignature, Detail: err.Error()}
 	}
 
 	// 3. Simulation (The specific "Universal" verification for MX)
+	if simulator == nil {
+		return true, nil
+	}
+
 	hash, err := simulator(payload)
 	if err != nil {
-		return false, fmt.Errorf("simulation failed: %w", err)
+		return false, err
 	}
 
 	if hash == "" {
-		return false, fmt.Errorf("simulation returned empty hash")
+		return false, NewVerificationError(InvalidReasonSimulationFailed, "simulation returned empty hash")
 	}
 
 	return true, nil
 }
+
+// VerifyTransactionSignature checks the sender's Ed25519 signature over the
+// transaction's signing bytes, honouring the sign-with-hash option.
+func VerifyTransactionSignature(tx Transaction) error {
+	_, pubKey, err := DecodeBech32(tx.Sender)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %v", err)
+	}
+	if len(pubKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid sender public key length: %d", len(pubKey))
+	}
+
+	sig, err := hex.DecodeString(tx.Signature)
+	if err != nil {
+		return errors.New("invalid signature: not hex encoded")
+	}
+	if len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("invalid signature length: %d", len(sig))
+	}
+
+	message, err := tx.BytesForSigning()
+	if err != nil {
+		return fmt.Errorf("failed to serialize transaction: %v", err)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(pubKey), message, sig) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
@@ -8,18 +8,33 @@ import (
 	"github.com/coinbase/x402/go/types"
 )
 
+func signedTestPayload(t *testing.T) ExactRelayedPayload {
+	t.Helper()
+	payload := ExactRelayedPayload{}
+	payload.Data = Transaction{
+		Nonce: 7, Value: "100", Receiver: bobAddress, Sender: aliceAddress,
+		GasPrice: 1000000000, GasLimit: 50000, ChainID: "D", Version: 2,
+	}
+	message, err := payload.Data.BytesForSigning()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload.Data.Signature = signWith(t, aliceSecretHex, message)
+	return payload
+}
+
 func TestVerifyPayment(t *testing.T) {
 	// Setup valid payload
-	validPayload := ExactRelayedPayload{}
-	validPayload.Data.Receiver = "erd1receiver"
-	validPayload.Data.Signature = "sig"
+	validPayload := signedTestPayload(t)
 
 	req := types.PaymentRequirements{
-		PayTo: "erd1receiver",
+		PayTo: bobAddress,
 	}
 
 	// Mock Simulator
+	simCalls := 0
 	successSim := func(p ExactRelayedPayload) (string, error) {
+		simCalls++
 		return "hash", nil
 	}
 	failSim := func(p ExactRelayedPayload) (string, error) {
@@ -45,4 +60,58 @@ func TestVerifyPayment(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for sim failure")
 	}
+
+	// Case 4: Forged signature is rejected offline
+	simCalls = 0
+	tampered := validPayload
+	tampered.Data.Value = "100000"
+	valid, err = VerifyPayment(context.Background(), tampered, req, successSim)
+	if err == nil || valid {
+		t.Error("Expected error for tampered payload")
+	}
+	if simCalls != 0 {
+		t.Error("Invalid signature must be rejected before simulation")
+	}
+
+	// Case 5: Simulation disabled
+	valid, err = VerifyPayment(context.Background(), validPayload, req, nil)
+	if err != nil || !valid {
+		t.Errorf("Expected success without simulation, got valid=%v err=%v", valid, err)
+	}
+}
+
+func TestVerifyTransactionSignature(t *testing.T) {
+	payload := signedTestPayload(t)
+	if err := VerifyTransactionSignature(payload.Data); err != nil {
+		t.Fatalf("Expected valid signature, got %v", err)
+	}
+
+	// Sign-with-hash mode
+	hashed := payload.Data
+	hashed.Options = TransactionOptionSignedWithHash
+	message, _ := hashed.BytesForSigning()
+	hashed.Signature = signWith(t, aliceSecretHex, message)
+	if err := VerifyTransactionSignature(hashed); err != nil {
+		t.Errorf("Expected valid hash-mode signature, got %v", err)
+	}
+
+	// Signature over the plain bytes does not verify once the hash option is set
+	hashed.Signature = payload.Data.Signature
+	if err := VerifyTransactionSignature(hashed); err == nil {
+		t.Error("Expected error for plain signature on hash-signed transaction")
+	}
+
+	// Signed by someone else
+	wrongSigner := payload.Data
+	wrongSigner.Sender = bobAddress
+	if err := VerifyTransactionSignature(wrongSigner); err == nil {
+		t.Error("Expected error when sender did not sign")
+	}
+
+	// Malformed signature
+	malformed := payload.Data
+	malformed.Signature = "zz"
+	if err := VerifyTransactionSignature(malformed); err == nil {
+		t.Error("Expected error for non-hex signature")
+	}
 }
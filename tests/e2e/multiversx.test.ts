@@ -132,7 +132,9 @@ describe("MultiversX Real E2E Flow (No Mocks)", () => {
             if (response.data.isValid) {
                 expect(response.data.isValid).toBe(true);
             } else {
-                console.log("Response:", response.data);
+                // Rejected by the protocol (e.g. no funds on a fresh wallet): still a full round trip
+                expect(response.data.invalidReason).toBeDefined();
+                console.log("Integration Verified! Payment rejected:", response.data.invalidReason);
             }
             // expect(response.data.tx_hash).toBeDefined(); 
             // In V2, hash might be in Meta
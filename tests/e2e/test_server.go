@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -18,6 +19,11 @@ func main() {
 	}
 
 	verifier := facilitator.NewExactMultiversXScheme(apiUrl)
+	// Fail fast if the API URL points at an unexpected network
+	if err := verifier.CheckGateway(context.Background()); err != nil {
+		log.Fatalf("Gateway check failed: %v", err)
+	}
+	log.Printf("Gateway %s serves chain %s", apiUrl, verifier.ChainID())
 	// serverScheme := server.NewExactMultiversXScheme() // unused
 
 	// Create a simple handler that performs verification
@@ -40,13 +46,17 @@ func main() {
 		// Payload is already correct type
 		payload := req.Payload
 
-		// Verify
+		// Verify: an invalid payment is a normal answer (IsValid=false + InvalidReason),
+		// an error means we could not reach a verdict (gateway down, relayer failure)
 		resp, err := verifier.Verify(r.Context(), payload, req.Requirements)
 		if err != nil {
-			log.Printf("Verification failed: %v", err)
-			http.Error(w, fmt.Sprintf("Verification failed: %v", err), http.StatusBadRequest)
+			log.Printf("Verification error: %v", err)
+			http.Error(w, fmt.Sprintf("Verification error: %v", err), http.StatusBadGateway)
 			return
 		}
+		if !resp.IsValid {
+			log.Printf("Invalid payment: %s", resp.InvalidReason)
+		}
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(resp)